@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	"golang.org/x/oauth2/jwt"
+)
+
+const (
+	// clientCredentialsGrant exchanges client_id & client_secret for access tokens
+	clientCredentialsGrant = "client_credentials"
+	// refreshTokenGrant exchanges refresh_token for access tokens
+	refreshTokenGrant = "refresh_token"
+	// jwtBearerGrant exchanges a JWT assertion signed with jwt_private_key for access tokens
+	jwtBearerGrant = "jwt_bearer"
+)
+
+// grantTypes are the supported values of the grant_type provider argument
+var grantTypes = []string{clientCredentialsGrant, refreshTokenGrant, jwtBearerGrant}
+
+// authSchema returns the provider arguments used to acquire access tokens from the identity provider's token endpoint
+func authSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"grant_type": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "oauth2 grant used to acquire & refresh access tokens from the token_endpoint of the open id connect metadata (client_credentials, refresh_token, jwt_bearer). access_token is used as-is when unset",
+			ValidateFunc: validation.StringInSlice(grantTypes, false),
+		},
+		"client_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "oauth2 client id (the JWT issuer of the jwt_bearer grant)",
+		},
+		"client_secret": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "oauth2 client secret",
+		},
+		"refresh_token": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "oauth2 refresh token used by the refresh_token grant",
+		},
+		"jwt_private_key": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "PEM encoded RSA private key used to sign the assertion of the jwt_bearer grant",
+		},
+		"jwt_private_key_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "key id of jwt_private_key",
+		},
+		"jwt_subject": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "subject of the assertion of the jwt_bearer grant",
+		},
+		"scopes": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "oauth2 scopes requested by the grant",
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		},
+	}
+}
+
+// tokenSource returns the source of the access tokens attached to every request to graphik.
+// Tokens acquired through a grant are refreshed transparently before they expire.
+// ctx must outlive the token source since it is used by every token request.
+func tokenSource(ctx context.Context, data *schema.ResourceData, accessToken string, metadata *oidcMetadata) (oauth2.TokenSource, error) {
+	grant := data.Get("grant_type").(string)
+	if grant == "" {
+		return oauth2.StaticTokenSource(&oauth2.Token{
+			AccessToken: accessToken,
+		}), nil
+	}
+	if metadata == nil || metadata.TokenEndpoint == "" {
+		return nil, errors.Errorf("the %s grant requires a token_endpoint in the open id connect metadata", grant)
+	}
+	var scopes []string
+	for _, scope := range data.Get("scopes").([]interface{}) {
+		scopes = append(scopes, scope.(string))
+	}
+	clientID := data.Get("client_id").(string)
+	switch grant {
+	case clientCredentialsGrant:
+		if clientID == "" || data.Get("client_secret").(string) == "" {
+			return nil, errors.New("the client_credentials grant requires client_id & client_secret")
+		}
+		config := &clientcredentials.Config{
+			ClientID:     clientID,
+			ClientSecret: data.Get("client_secret").(string),
+			TokenURL:     metadata.TokenEndpoint,
+			Scopes:       scopes,
+		}
+		return config.TokenSource(ctx), nil
+	case refreshTokenGrant:
+		refreshToken := data.Get("refresh_token").(string)
+		if refreshToken == "" {
+			return nil, errors.New("the refresh_token grant requires refresh_token")
+		}
+		config := &oauth2.Config{
+			ClientID:     clientID,
+			ClientSecret: data.Get("client_secret").(string),
+			Endpoint:     oauth2.Endpoint{TokenURL: metadata.TokenEndpoint},
+			Scopes:       scopes,
+		}
+		// the token has no access token so the first request exchanges the refresh token
+		return config.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}), nil
+	case jwtBearerGrant:
+		privateKey := data.Get("jwt_private_key").(string)
+		if clientID == "" || privateKey == "" {
+			return nil, errors.New("the jwt_bearer grant requires client_id & jwt_private_key")
+		}
+		config := &jwt.Config{
+			Email:        clientID,
+			PrivateKey:   []byte(privateKey),
+			PrivateKeyID: data.Get("jwt_private_key_id").(string),
+			Subject:      data.Get("jwt_subject").(string),
+			Scopes:       scopes,
+			TokenURL:     metadata.TokenEndpoint,
+		}
+		return config.TokenSource(ctx), nil
+	default:
+		return nil, errors.Errorf("unsupported grant_type: %s", grant)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTokenSource(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token": %q, "token_type": "bearer", "expires_in": 3600}`, r.PostForm.Get("grant_type"))
+	}))
+	defer server.Close()
+	providerSchema := Provider().(*schema.Provider).Schema
+	for _, tc := range []struct {
+		raw   map[string]interface{}
+		token string
+		err   string
+	}{
+		{raw: map[string]interface{}{}, token: "static"},
+		{raw: map[string]interface{}{"grant_type": "client_credentials", "client_id": "terraform", "client_secret": "secret"}, token: "client_credentials"},
+		{raw: map[string]interface{}{"grant_type": "refresh_token", "client_id": "terraform", "refresh_token": "refresh"}, token: "refresh_token"},
+		{raw: map[string]interface{}{"grant_type": "jwt_bearer", "client_id": "terraform", "jwt_private_key": privateKey}, token: "urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		{raw: map[string]interface{}{"grant_type": "client_credentials", "client_id": "terraform"}, err: "requires client_id & client_secret"},
+	} {
+		tokens, err := tokenSource(context.Background(), schema.TestResourceDataRaw(t, providerSchema, tc.raw), "static", &oidcMetadata{TokenEndpoint: server.URL})
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%v: expected error containing %q, got: %v", tc.raw, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%v: %s", tc.raw, err)
+		}
+		token, err := tokens.Token()
+		if err != nil {
+			t.Fatalf("%v: %s", tc.raw, err)
+		}
+		if token.AccessToken != tc.token {
+			t.Errorf("%v: expected access token %q, got: %q", tc.raw, tc.token, token.AccessToken)
+		}
+	}
+}
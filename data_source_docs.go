@@ -0,0 +1,194 @@
+package main
+
+import (
+	"context"
+	apipb "github.com/graphikDB/graphik/gen/grpc/go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"regexp"
+)
+
+// docsSortPattern matches the fields graphik can sort docs by
+var docsSortPattern = regexp.MustCompile(`^(ref\.gid|ref\.gtype|attributes\..+)$`)
+
+// dataSourceDocs searches the docs of a type with a CEL expression, ex: to connect a new doc to an existing team
+func dataSourceDocs() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"gtype": {
+				Type:         schema.TypeString,
+				Required:     true,
+				Description:  "the type of the docs to search ex: team",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"expression": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "boolean CEL expression the docs must pass ex: this.attributes.division == 'platform'. every doc of the type matches when unset",
+				ValidateFunc: validateCEL,
+			},
+			"limit": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      100,
+				Description:  "the maximum number of docs returned",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"sort": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "the field the docs are sorted by: ref.gid, ref.gtype or attributes.<key>",
+				ValidateFunc: validation.StringMatch(docsSortPattern, "expected ref.gid, ref.gtype or attributes.<key>"),
+			},
+			"reverse": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "search the docs in reverse order",
+			},
+			"index": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "the name of an index of the docs of gtype (ex: one managed by graphik_index) used to narrow the search",
+			},
+			"docs": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "the matching docs",
+				Elem: &schema.Resource{
+					Schema: docAttributes(),
+				},
+			},
+		},
+		Read: func(data *schema.ResourceData, i interface{}) error {
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.requestTimeout)
+			defer cancel()
+			filter := &apipb.Filter{
+				Gtype:      data.Get("gtype").(string),
+				Expression: data.Get("expression").(string),
+				Limit:      uint64(data.Get("limit").(int)),
+				Sort:       data.Get("sort").(string),
+				Reverse:    data.Get("reverse").(bool),
+				Index:      data.Get("index").(string),
+			}
+			if filter.GetIndex() != "" {
+				// graphik can't tell an unknown index from one without docs, so check it exists up front
+				scheme, err := meta.getSchema(ctx)
+				if err != nil {
+					return err
+				}
+				index := findIndex(filter.GetIndex(), scheme.GetIndexes().GetIndexes())
+				if index == nil {
+					return errors.Errorf("index not found: %s", filter.GetIndex())
+				}
+				if !index.GetTargetDocs() || (index.GetGtype() != filter.GetGtype() && index.GetGtype() != apipb.Any) {
+					return errors.Errorf("index %s doesn't index docs of type %s", index.GetName(), filter.GetGtype())
+				}
+			}
+			var docs *apipb.Docs
+			err := meta.retry(ctx, func() error {
+				var err error
+				docs, err = meta.client.SearchDocs(ctx, filter)
+				return err
+			})
+			if err != nil && status.Code(err) != codes.NotFound {
+				// graphik returns NotFound when there are no docs of the type
+				return err
+			}
+			values := make([]interface{}, 0, len(docs.GetDocs()))
+			for _, doc := range docs.GetDocs() {
+				value, err := flattenDoc(doc)
+				if err != nil {
+					return err
+				}
+				values = append(values, value)
+			}
+			if err := data.Set("docs", values); err != nil {
+				return err
+			}
+			data.SetId(filter.GetGtype())
+			return nil
+		},
+		Description: "the docs of a type that pass a CEL expression",
+	}
+}
+
+// dataSourceDoc looks up an existing doc by ref
+func dataSourceDoc() *schema.Resource {
+	attributes := docAttributes()
+	for _, k := range []string{"gtype", "gid"} {
+		attributes[k].Computed = false
+		attributes[k].Required = true
+		attributes[k].ValidateFunc = validation.StringIsNotEmpty
+	}
+	return &schema.Resource{
+		Schema: attributes,
+		Read: func(data *schema.ResourceData, i interface{}) error {
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.requestTimeout)
+			defer cancel()
+			ref := &apipb.Ref{
+				Gtype: data.Get("gtype").(string),
+				Gid:   data.Get("gid").(string),
+			}
+			var doc *apipb.Doc
+			err := meta.retry(ctx, func() error {
+				var err error
+				doc, err = meta.client.GetDoc(ctx, ref)
+				return err
+			})
+			if status.Code(err) == codes.NotFound {
+				return errors.Errorf("doc not found: %s", refID(ref))
+			}
+			if err != nil {
+				return err
+			}
+			value, err := flattenDoc(doc)
+			if err != nil {
+				return err
+			}
+			if err := data.Set("attributes", value["attributes"]); err != nil {
+				return err
+			}
+			data.SetId(refID(doc.GetRef()))
+			return nil
+		},
+		Description: "a doc(node) in the graph",
+	}
+}
+
+// docAttributes returns the computed attributes of a single doc
+func docAttributes() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"gtype": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "the type of the doc",
+		},
+		"gid": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "the unique id of the doc within its type",
+		},
+		"attributes": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "JSON encoded attributes of the doc - use jsondecode() to read them",
+		},
+	}
+}
+
+func flattenDoc(doc *apipb.Doc) (map[string]interface{}, error) {
+	attributes, err := attributesJSON(doc.GetAttributes())
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"gtype":      doc.GetRef().GetGtype(),
+		"gid":        doc.GetRef().GetGid(),
+		"attributes": attributes,
+	}, nil
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	apipb "github.com/graphikDB/graphik/gen/grpc/go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"google.golang.org/protobuf/types/known/structpb"
+	"regexp"
+	"testing"
+)
+
+func TestAccDocsDataSources(t *testing.T) {
+	client := newFakeClient()
+	client.seed()
+	for _, gid := range []string{"platform", "data", "security"} {
+		attributes, _ := structpb.NewStruct(map[string]interface{}{"division": "engineering"})
+		client.docs["team/"+gid] = &apipb.Doc{Ref: &apipb.Ref{Gtype: "team", Gid: gid}, Attributes: attributes}
+	}
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testProviders(client),
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+data "graphik_docs" "teams" {
+  gtype      = "team"
+  expression = "this.attributes.division == 'engineering'"
+  limit      = 2
+  reverse    = true
+}
+
+data "graphik_docs" "none" {
+  gtype = "division"
+}
+
+data "graphik_doc" "platform" {
+  gtype = "team"
+  gid   = "platform"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.graphik_docs.teams", "docs.#", "2"),
+					resource.TestCheckResourceAttr("data.graphik_docs.teams", "docs.0.gid", "security"),
+					resource.TestCheckResourceAttr("data.graphik_docs.teams", "docs.1.gid", "platform"),
+					resource.TestCheckResourceAttr("data.graphik_docs.teams", "docs.1.gtype", "team"),
+					resource.TestCheckResourceAttr("data.graphik_docs.teams", "docs.1.attributes", `{"division":"engineering"}`),
+					resource.TestCheckResourceAttr("data.graphik_docs.none", "docs.#", "0"),
+					resource.TestCheckResourceAttr("data.graphik_doc.platform", "id", "team/platform"),
+					resource.TestCheckResourceAttr("data.graphik_doc.platform", "attributes", `{"division":"engineering"}`),
+				),
+			},
+			{
+				Config: testProviderConfig + `
+data "graphik_docs" "teams" {
+  gtype = "team"
+  index = "a"
+}
+`,
+				ExpectError: regexp.MustCompile("index a doesn't index docs of type team"),
+			},
+			{
+				Config: testProviderConfig + `
+data "graphik_docs" "teams" {
+  gtype = "team"
+  index = "missing"
+}
+`,
+				ExpectError: regexp.MustCompile("index not found: missing"),
+			},
+			{
+				Config: testProviderConfig + `
+data "graphik_docs" "teams" {
+  gtype = "team"
+  sort  = "name"
+}
+`,
+				ExpectError: regexp.MustCompile("expected ref.gid, ref.gtype or attributes.<key>"),
+			},
+			{
+				Config: testProviderConfig + `
+data "graphik_doc" "missing" {
+  gtype = "team"
+  gid   = "missing"
+}
+`,
+				ExpectError: regexp.MustCompile("doc not found: team/missing"),
+			},
+		},
+	})
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/golang/protobuf/ptypes/empty"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	apipb "github.com/graphikDB/graphik/gen/grpc/go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// dataSourceMe exposes the identity the provider is authenticated as
+func dataSourceMe() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"gtype": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the type of the doc representing the authenticated identity",
+			},
+			"gid": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "the unique id of the doc representing the authenticated identity",
+			},
+			"attributes": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "JSON encoded attributes of the authenticated identity (ex: email, name) - use jsondecode() to read them",
+			},
+		},
+		Read: func(data *schema.ResourceData, i interface{}) error {
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.requestTimeout)
+			defer cancel()
+			var me *apipb.Doc
+			err := meta.retry(ctx, func() error {
+				var err error
+				me, err = meta.client.Me(ctx, &empty.Empty{})
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			attributes, err := attributesJSON(me.GetAttributes())
+			if err != nil {
+				return err
+			}
+			if err := data.Set("gtype", me.GetRef().GetGtype()); err != nil {
+				return err
+			}
+			if err := data.Set("gid", me.GetRef().GetGid()); err != nil {
+				return err
+			}
+			if err := data.Set("attributes", attributes); err != nil {
+				return err
+			}
+			data.SetId(refID(me.GetRef()))
+			return nil
+		},
+		Description: "the identity(doc) the provider is authenticated as",
+	}
+}
+
+// refID returns the terraform id of the doc/connection with the given ref: <gtype>/<gid>
+func refID(ref *apipb.Ref) string {
+	return ref.GetGtype() + "/" + ref.GetGid()
+}
+
+// attributesJSON encodes the attributes of a doc/connection as JSON with sorted keys so it can be compared as a string
+func attributesJSON(attributes *_struct.Struct) (string, error) {
+	bits, err := json.Marshal(attributes.AsMap())
+	if err != nil {
+		return "", err
+	}
+	return string(bits), nil
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"testing"
+)
+
+func TestAccMeDataSource(t *testing.T) {
+	client := newFakeClient()
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testProviders(client),
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+data "graphik_me" "me" {}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.graphik_me.me", "id", "user/terraform"),
+					resource.TestCheckResourceAttr("data.graphik_me.me", "gtype", "user"),
+					resource.TestCheckResourceAttr("data.graphik_me.me", "gid", "terraform"),
+					resource.TestCheckResourceAttr("data.graphik_me.me", "attributes", `{"email":"terraform@example.com"}`),
+				),
+			},
+		},
+	})
+}
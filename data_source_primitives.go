@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/pkg/errors"
+)
+
+// dataSourceIndex looks up an existing index by name
+func dataSourceIndex() *schema.Resource {
+	return &schema.Resource{
+		Schema: lookupAttributes(indexAttributes()),
+		Read: func(data *schema.ResourceData, i interface{}) error {
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.requestTimeout)
+			defer cancel()
+			scheme, err := meta.getSchema(ctx)
+			if err != nil {
+				return err
+			}
+			name := data.Get("name").(string)
+			index := findIndex(name, scheme.GetIndexes().GetIndexes())
+			if index == nil {
+				return errors.Errorf("index not found: %s", name)
+			}
+			if err := setIndexData(data, index); err != nil {
+				return err
+			}
+			data.SetId(index.GetName())
+			return nil
+		},
+		Description: "a graph primitive used for fast lookups of docs/connections that pass a boolean CEL expression",
+	}
+}
+
+// dataSourceTrigger looks up an existing trigger by name
+func dataSourceTrigger() *schema.Resource {
+	return &schema.Resource{
+		Schema: lookupAttributes(triggerAttributes()),
+		Read: func(data *schema.ResourceData, i interface{}) error {
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.requestTimeout)
+			defer cancel()
+			scheme, err := meta.getSchema(ctx)
+			if err != nil {
+				return err
+			}
+			name := data.Get("name").(string)
+			trigger := findTrigger(name, scheme.GetTriggers().GetTriggers())
+			if trigger == nil {
+				return errors.Errorf("trigger not found: %s", name)
+			}
+			if err := setTriggerData(data, trigger); err != nil {
+				return err
+			}
+			data.SetId(trigger.GetName())
+			return nil
+		},
+		Description: "used to automatically mutate the attributes of documents/connections before they are commited to the database",
+	}
+}
+
+// dataSourceConstraint looks up an existing constraint by name
+func dataSourceConstraint() *schema.Resource {
+	return &schema.Resource{
+		Schema: lookupAttributes(constraintAttributes()),
+		Read: func(data *schema.ResourceData, i interface{}) error {
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.requestTimeout)
+			defer cancel()
+			scheme, err := meta.getSchema(ctx)
+			if err != nil {
+				return err
+			}
+			name := data.Get("name").(string)
+			constraint := findConstraint(name, scheme.GetConstraints().GetConstraints())
+			if constraint == nil {
+				return errors.Errorf("constraint not found: %s", name)
+			}
+			if err := setConstraintData(data, constraint); err != nil {
+				return err
+			}
+			data.SetId(constraint.GetName())
+			return nil
+		},
+		Description: "a graph primitive used to validate custom doc/connection constraints",
+	}
+}
+
+// dataSourceAuthorizer looks up an existing authorizer by name
+func dataSourceAuthorizer() *schema.Resource {
+	return &schema.Resource{
+		Schema: lookupAttributes(authorizerAttributes()),
+		Read: func(data *schema.ResourceData, i interface{}) error {
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.requestTimeout)
+			defer cancel()
+			scheme, err := meta.getSchema(ctx)
+			if err != nil {
+				return err
+			}
+			name := data.Get("name").(string)
+			authorizer := findAuthorizer(name, scheme.GetAuthorizers().GetAuthorizers())
+			if authorizer == nil {
+				return errors.Errorf("authorizer not found: %s", name)
+			}
+			if err := setAuthorizerData(data, authorizer); err != nil {
+				return err
+			}
+			data.SetId(authorizer.GetName())
+			return nil
+		},
+		Description: "a graph primitive used for authorizing inbound requests and/or responses(see AuthTarget)",
+	}
+}
+
+// lookupAttributes makes name a required argument of the given computed attributes so a primitive may be looked up by it
+func lookupAttributes(attributes map[string]*schema.Schema) map[string]*schema.Schema {
+	name := attributes["name"]
+	name.Computed = false
+	name.Required = true
+	name.ValidateFunc = validation.StringIsNotEmpty
+	return attributes
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"regexp"
+	"testing"
+)
+
+func TestAccPrimitiveDataSources(t *testing.T) {
+	client := newFakeClient()
+	client.seed()
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testProviders(client),
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+data "graphik_index" "b" {
+  name = "b"
+}
+
+data "graphik_trigger" "b" {
+  name = "b"
+}
+
+data "graphik_constraint" "b" {
+  name = "b"
+}
+
+data "graphik_authorizer" "b" {
+  name = "b"
+}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.graphik_index.b", "gtype", "task"),
+					resource.TestCheckResourceAttr("data.graphik_index.b", "target_docs", "true"),
+					resource.TestCheckResourceAttr("data.graphik_trigger.b", "trigger", "{'updated_at': now()}"),
+					resource.TestCheckResourceAttr("data.graphik_constraint.b", "expression", "true"),
+					resource.TestCheckResourceAttr("data.graphik_authorizer.b", "method", "/api.DatabaseService/GetSchema"),
+					resource.TestCheckResourceAttr("data.graphik_authorizer.b", "target_requests", "true"),
+				),
+			},
+			{
+				Config: testProviderConfig + `
+data "graphik_authorizer" "missing" {
+  name = "missing"
+}
+`,
+				ExpectError: regexp.MustCompile("authorizer not found: missing"),
+			},
+		},
+	})
+}
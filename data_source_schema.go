@@ -0,0 +1,268 @@
+package main
+
+import (
+	"context"
+	apipb "github.com/graphikDB/graphik/gen/grpc/go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// dataSourceSchema exposes the live schema of the graphikDB instance
+func dataSourceSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"connection_types": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "the types of connections in the graph",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"doc_types": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "the types of docs in the graph",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"indexes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "the indexes registered in the graph",
+				Elem:        &schema.Resource{Schema: indexAttributes()},
+			},
+			"triggers": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "the triggers registered in the graph",
+				Elem:        &schema.Resource{Schema: triggerAttributes()},
+			},
+			"constraints": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "the constraints registered in the graph",
+				Elem:        &schema.Resource{Schema: constraintAttributes()},
+			},
+			"authorizers": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "the authorizers registered in the graph",
+				Elem:        &schema.Resource{Schema: authorizerAttributes()},
+			},
+		},
+		Read: func(data *schema.ResourceData, i interface{}) error {
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.requestTimeout)
+			defer cancel()
+			scheme, err := meta.getSchema(ctx)
+			if err != nil {
+				return err
+			}
+			var indexes []interface{}
+			for _, v := range scheme.GetIndexes().GetIndexes() {
+				indexes = append(indexes, flattenIndex(v))
+			}
+			var triggers []interface{}
+			for _, v := range scheme.GetTriggers().GetTriggers() {
+				triggers = append(triggers, flattenTrigger(v))
+			}
+			var constraints []interface{}
+			for _, v := range scheme.GetConstraints().GetConstraints() {
+				constraints = append(constraints, flattenConstraint(v))
+			}
+			var authorizers []interface{}
+			for _, v := range scheme.GetAuthorizers().GetAuthorizers() {
+				authorizers = append(authorizers, flattenAuthorizer(v))
+			}
+			if err := data.Set("connection_types", scheme.GetConnectionTypes()); err != nil {
+				return err
+			}
+			if err := data.Set("doc_types", scheme.GetDocTypes()); err != nil {
+				return err
+			}
+			if err := data.Set("indexes", indexes); err != nil {
+				return err
+			}
+			if err := data.Set("triggers", triggers); err != nil {
+				return err
+			}
+			if err := data.Set("constraints", constraints); err != nil {
+				return err
+			}
+			if err := data.Set("authorizers", authorizers); err != nil {
+				return err
+			}
+			data.SetId("schema")
+			return nil
+		},
+		Description: "the live schema(doc types, connection types, indexes, triggers, constraints & authorizers) of a graphikDB instance",
+	}
+}
+
+// indexAttributes returns the computed attributes of an index
+func indexAttributes() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "unique name of the index",
+		},
+		"gtype": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "the doc/connection type to be indexed",
+		},
+		"expression": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "boolean CEL expression used to filter docs/connections",
+		},
+		"target_docs": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "whether the index is applied to docs",
+		},
+		"target_connections": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "whether the index is applied to connections",
+		},
+	}
+}
+
+// triggerAttributes returns the computed attributes of a trigger
+func triggerAttributes() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "unique name of the trigger",
+		},
+		"gtype": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "the doc/connection type that invokes the trigger",
+		},
+		"expression": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "boolean CEL expression that gates whether the trigger is applied to a doc/connection",
+		},
+		"trigger": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "CEL expression returning the attributes to merge into the doc/connection before it is stored",
+		},
+		"target_docs": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "whether the trigger is applied to docs",
+		},
+		"target_connections": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "whether the trigger is applied to connections",
+		},
+	}
+}
+
+// constraintAttributes returns the computed attributes of a constraint
+func constraintAttributes() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "unique name of the constraint",
+		},
+		"gtype": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "the doc/connection type to be validated",
+		},
+		"expression": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "boolean CEL expression that docs/connections must pass",
+		},
+		"target_docs": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "whether the constraint is applied to docs",
+		},
+		"target_connections": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "whether the constraint is applied to connections",
+		},
+	}
+}
+
+// authorizerAttributes returns the computed attributes of an authorizer
+func authorizerAttributes() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "unique name of the authorizer",
+		},
+		"method": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "the gRPC method that invokes the authorizer",
+		},
+		"expression": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "boolean CEL expression that evaluates the request and/or response",
+		},
+		"target_requests": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "whether the authorizer evaluates requests",
+		},
+		"target_responses": {
+			Type:        schema.TypeBool,
+			Computed:    true,
+			Description: "whether the authorizer evaluates responses",
+		},
+	}
+}
+
+func flattenIndex(value *apipb.Index) map[string]interface{} {
+	return map[string]interface{}{
+		"name":               value.GetName(),
+		"gtype":              value.GetGtype(),
+		"expression":         value.GetExpression(),
+		"target_docs":        value.GetTargetDocs(),
+		"target_connections": value.GetTargetConnections(),
+	}
+}
+
+func flattenTrigger(value *apipb.Trigger) map[string]interface{} {
+	expression, trigger := splitTriggerArrow(value.GetTrigger())
+	return map[string]interface{}{
+		"name":               value.GetName(),
+		"gtype":              value.GetGtype(),
+		"expression":         expression,
+		"trigger":            trigger,
+		"target_docs":        value.GetTargetDocs(),
+		"target_connections": value.GetTargetConnections(),
+	}
+}
+
+func flattenConstraint(value *apipb.Constraint) map[string]interface{} {
+	return map[string]interface{}{
+		"name":               value.GetName(),
+		"gtype":              value.GetGtype(),
+		"expression":         value.GetExpression(),
+		"target_docs":        value.GetTargetDocs(),
+		"target_connections": value.GetTargetConnections(),
+	}
+}
+
+func flattenAuthorizer(value *apipb.Authorizer) map[string]interface{} {
+	return map[string]interface{}{
+		"name":             value.GetName(),
+		"method":           value.GetMethod(),
+		"expression":       value.GetExpression(),
+		"target_requests":  value.GetTargetRequests(),
+		"target_responses": value.GetTargetResponses(),
+	}
+}
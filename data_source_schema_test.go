@@ -0,0 +1,32 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"testing"
+)
+
+func TestAccSchemaDataSource(t *testing.T) {
+	client := newFakeClient()
+	client.seed()
+	client.schema.DocTypes = []string{"task", "user"}
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testProviders(client),
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+data "graphik_schema" "live" {}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.graphik_schema.live", "doc_types.#", "2"),
+					resource.TestCheckResourceAttr("data.graphik_schema.live", "doc_types.1", "user"),
+					resource.TestCheckResourceAttr("data.graphik_schema.live", "indexes.#", "3"),
+					resource.TestCheckResourceAttr("data.graphik_schema.live", "indexes.0.name", "a"),
+					resource.TestCheckResourceAttr("data.graphik_schema.live", "triggers.2.expression", "true"),
+					resource.TestCheckResourceAttr("data.graphik_schema.live", "triggers.2.trigger", "{'updated_at': now()}"),
+					resource.TestCheckResourceAttr("data.graphik_schema.live", "constraints.#", "3"),
+					resource.TestCheckResourceAttr("data.graphik_schema.live", "authorizers.1.method", "/api.DatabaseService/GetSchema"),
+				),
+			},
+		},
+	})
+}
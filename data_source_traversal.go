@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	apipb "github.com/graphikDB/graphik/gen/grpc/go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"sort"
+)
+
+// dataSourceTraversal walks the graph from a root doc, ex: every team under a division of an org hierarchy
+func dataSourceTraversal() *schema.Resource {
+	traversed := docAttributes()
+	traversed["path"] = &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "the refs of the docs traversed from the root to reach the doc, in order",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"gtype": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "the type of the doc",
+				},
+				"gid": {
+					Type:        schema.TypeString,
+					Computed:    true,
+					Description: "the unique id of the doc within its type",
+				},
+			},
+		},
+	}
+	traversed["depth"] = &schema.Schema{
+		Type:        schema.TypeInt,
+		Computed:    true,
+		Description: "the number of connections between the root & the doc",
+	}
+	traversed["hops"] = &schema.Schema{
+		Type:        schema.TypeInt,
+		Computed:    true,
+		Description: "the number of docs visited before reaching the doc",
+	}
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"root": refSchema("the doc the traversal starts from", false),
+			"doc_expression": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "boolean CEL expression the traversed docs must pass ex: this.ref.gtype == 'team'",
+				ValidateFunc: validateCEL,
+			},
+			"connection_expression": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "boolean CEL expression the connections followed must pass ex: this.ref.gtype == 'owns'",
+				ValidateFunc: validateCEL,
+			},
+			"algorithm": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      apipb.Algorithm_BFS.String(),
+				Description:  "the search algorithm: BFS(breadth-first) or DFS(depth-first)",
+				ValidateFunc: validation.StringInSlice(algorithmNames(), false),
+			},
+			"max_depth": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				Description:  "the maximum number of connections between the root & a traversed doc",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"max_hops": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				Description:  "the maximum number of docs visited",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"limit": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      100,
+				Description:  "the maximum number of docs returned",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"sort": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "the field the docs are sorted by: ref.gid, ref.gtype or attributes.<key>. docs are returned in traversal order when unset",
+				ValidateFunc: validation.StringMatch(docsSortPattern, "expected ref.gid, ref.gtype or attributes.<key>"),
+			},
+			"reverse": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "reverse the sort order",
+			},
+			"traversals": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "the traversed docs that pass doc_expression",
+				Elem: &schema.Resource{
+					Schema: traversed,
+				},
+			},
+		},
+		Read: func(data *schema.ResourceData, i interface{}) error {
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.requestTimeout)
+			defer cancel()
+			filter := &apipb.TraverseFilter{
+				Root:                 expandRef(data.Get("root")),
+				DocExpression:        data.Get("doc_expression").(string),
+				ConnectionExpression: data.Get("connection_expression").(string),
+				Limit:                uint64(data.Get("limit").(int)),
+				Sort:                 data.Get("sort").(string),
+				Reverse:              data.Get("reverse").(bool),
+				Algorithm:            apipb.Algorithm(apipb.Algorithm_value[data.Get("algorithm").(string)]),
+				MaxDepth:             uint64(data.Get("max_depth").(int)),
+				MaxHops:              uint64(data.Get("max_hops").(int)),
+			}
+			var traversals *apipb.Traversals
+			err := meta.retry(ctx, func() error {
+				var err error
+				traversals, err = meta.client.Traverse(ctx, filter)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			values := make([]interface{}, 0, len(traversals.GetTraversals()))
+			for _, traversal := range traversals.GetTraversals() {
+				value, err := flattenDoc(traversal.GetDoc())
+				if err != nil {
+					return err
+				}
+				path := make([]interface{}, 0, len(traversal.GetTraversalPath()))
+				for _, ref := range traversal.GetTraversalPath() {
+					path = append(path, flattenRef(ref)...)
+				}
+				value["path"] = path
+				value["depth"] = int(traversal.GetDepth())
+				value["hops"] = int(traversal.GetHops())
+				values = append(values, value)
+			}
+			if err := data.Set("traversals", values); err != nil {
+				return err
+			}
+			data.SetId(refID(filter.GetRoot()))
+			return nil
+		},
+		Description: "the docs reachable from a root doc by following connections",
+	}
+}
+
+// algorithmNames returns the names of the search algorithms graphik supports
+func algorithmNames() []string {
+	var names []string
+	for name := range apipb.Algorithm_value {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
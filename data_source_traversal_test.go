@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	apipb "github.com/graphikDB/graphik/gen/grpc/go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/structpb"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestAccTraversalDataSource(t *testing.T) {
+	client := newFakeClient()
+	for _, ref := range []string{"division/engineering", "team/platform", "team/data", "team/storage"} {
+		parsed, _ := parseRefID(ref)
+		client.docs[ref] = &apipb.Doc{Ref: parsed, Attributes: &structpb.Struct{}}
+	}
+	for i, edge := range [][2]string{
+		{"division/engineering", "team/platform"},
+		{"division/engineering", "team/data"},
+		{"team/platform", "team/storage"},
+	} {
+		from, _ := parseRefID(edge[0])
+		to, _ := parseRefID(edge[1])
+		ref := &apipb.Ref{Gtype: "owns", Gid: strconv.Itoa(i)}
+		client.connections[refID(ref)] = &apipb.Connection{Ref: ref, From: from, To: to, Directed: true}
+	}
+	config := func(algorithm string, maxDepth int) string {
+		return testProviderConfig + fmt.Sprintf(`
+data "graphik_traversal" "teams" {
+  root {
+    gtype = "division"
+    gid   = "engineering"
+  }
+  doc_expression        = "this.ref.gtype == 'team'"
+  connection_expression = "this.ref.gtype == 'owns'"
+  algorithm             = %q
+  max_depth             = %v
+  max_hops              = 10
+}
+`, algorithm, maxDepth)
+	}
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testProviders(client),
+		Steps: []resource.TestStep{
+			{
+				Config:      config("A*", 1),
+				ExpectError: regexp.MustCompile(`expected algorithm to be one of \[BFS DFS\]`),
+			},
+			{
+				Config: config("BFS", 2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.graphik_traversal.teams", "id", "division/engineering"),
+					resource.TestCheckResourceAttr("data.graphik_traversal.teams", "traversals.#", "4"),
+					resource.TestCheckResourceAttr("data.graphik_traversal.teams", "traversals.2.gid", "data"),
+					resource.TestCheckResourceAttr("data.graphik_traversal.teams", "traversals.3.gid", "storage"),
+					resource.TestCheckResourceAttr("data.graphik_traversal.teams", "traversals.3.depth", "2"),
+					resource.TestCheckResourceAttr("data.graphik_traversal.teams", "traversals.3.hops", "4"),
+					resource.TestCheckResourceAttr("data.graphik_traversal.teams", "traversals.3.path.#", "2"),
+					resource.TestCheckResourceAttr("data.graphik_traversal.teams", "traversals.3.path.0.gid", "engineering"),
+					resource.TestCheckResourceAttr("data.graphik_traversal.teams", "traversals.3.path.1.gtype", "team"),
+					resource.TestCheckResourceAttr("data.graphik_traversal.teams", "traversals.3.path.1.gid", "platform"),
+					func(state *terraform.State) error {
+						expected := &apipb.TraverseFilter{
+							Root:                 &apipb.Ref{Gtype: "division", Gid: "engineering"},
+							DocExpression:        "this.ref.gtype == 'team'",
+							ConnectionExpression: "this.ref.gtype == 'owns'",
+							Limit:                100,
+							Algorithm:            apipb.Algorithm_BFS,
+							MaxDepth:             2,
+							MaxHops:              10,
+						}
+						if !proto.Equal(client.traverseFilter, expected) {
+							return errors.Errorf("unexpected traverse filter: %v", client.traverseFilter)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: config("DFS", 1),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.graphik_traversal.teams", "traversals.#", "3"),
+					resource.TestCheckResourceAttr("data.graphik_traversal.teams", "traversals.1.gid", "platform"),
+					resource.TestCheckResourceAttr("data.graphik_traversal.teams", "traversals.2.gid", "data"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	apipb "github.com/graphikDB/graphik/gen/grpc/go"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/pkg/errors"
+	"github.com/zclconf/go-cty/cty"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// exportCommand is the subcommand of the plugin binary that prints the live schema as terraform configuration
+const exportCommand = "export"
+
+// exportKinds are the kinds of schema primitives exported, in output order, with the attributes of each in output order
+var exportKinds = []struct {
+	bulk       bulkKind
+	attributes []string
+}{
+	{bulk: bulkIndexes, attributes: []string{"name", "gtype", "expression", "target_docs", "target_connections"}},
+	{bulk: bulkTriggers, attributes: []string{"name", "gtype", "expression", "trigger", "target_docs", "target_connections"}},
+	{bulk: bulkConstraints, attributes: []string{"name", "gtype", "expression", "target_docs", "target_connections"}},
+	{bulk: bulkAuthorizers, attributes: []string{"name", "method", "expression", "target_requests", "target_responses"}},
+}
+
+// invalidLabelChars matches the characters that aren't allowed in a terraform resource name
+var invalidLabelChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// runExport implements the export subcommand: it connects to graphik the same way the provider does
+// and prints a resource block (and an import block for terraform 1.5+) for every schema primitive
+func runExport(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet(exportCommand, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	provider := Provider().(*schema.Provider)
+	providerConfig := exportFlags(flags, provider.Schema)
+	imports := flags.Bool("imports", true, "print an import block for every resource block (terraform 1.5+)")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: terraform-provider-graphik %s [flags]\n\nprints the indexes, triggers, constraints & authorizers of a graphikDB instance as terraform configuration\n\n", exportCommand)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	config := terraform.NewResourceConfigRaw(providerConfig())
+	if _, errs := provider.Validate(config); len(errs) > 0 {
+		for _, err := range errs {
+			fmt.Fprintln(stderr, err)
+		}
+		return 2
+	}
+	if err := provider.Configure(config); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	meta := provider.Meta().(*providerMeta)
+	ctx, cancel := context.WithTimeout(context.Background(), meta.requestTimeout)
+	defer cancel()
+	scheme, err := meta.getSchema(ctx)
+	if err != nil {
+		fmt.Fprintln(stderr, errors.Wrap(err, "failed to get schema"))
+		return 1
+	}
+	if _, err := stdout.Write(exportSchema(scheme, *imports)); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// exportFlags registers a flag for every provider argument & returns a func building the provider config from the parsed flags.
+// Arguments without a flag fall back to the environment & graphikctl config like the provider. List arguments are comma separated.
+func exportFlags(flags *flag.FlagSet, arguments map[string]*schema.Schema) func() map[string]interface{} {
+	var names []string
+	for name := range arguments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		argument := arguments[name]
+		usage := "the " + name + " provider argument: " + argument.Description
+		switch argument.Type {
+		case schema.TypeBool:
+			flags.Bool(name, false, usage)
+		case schema.TypeInt:
+			flags.Int(name, 0, usage)
+		case schema.TypeList:
+			flags.String(name, "", usage+" (comma separated)")
+		default:
+			flags.String(name, "", usage)
+		}
+	}
+	return func() map[string]interface{} {
+		config := map[string]interface{}{}
+		// only the flags that were set are configured so unset arguments keep their defaults
+		flags.Visit(func(f *flag.Flag) {
+			argument, ok := arguments[f.Name]
+			if !ok {
+				return
+			}
+			value := f.Value.(flag.Getter).Get()
+			if argument.Type == schema.TypeList {
+				var values []interface{}
+				for _, v := range strings.Split(value.(string), ",") {
+					if v = strings.TrimSpace(v); v != "" {
+						values = append(values, v)
+					}
+				}
+				value = values
+			}
+			config[f.Name] = value
+		})
+		return config
+	}
+}
+
+// exportSchema returns the terraform configuration managing every primitive of the schema
+func exportSchema(scheme *apipb.Schema, imports bool) []byte {
+	file := hclwrite.NewEmptyFile()
+	body := file.Body()
+	for _, kind := range exportKinds {
+		resourceType := "graphik_" + kind.bulk.block
+		labels := map[string]bool{}
+		for _, v := range kind.bulk.flatten(scheme) {
+			value := v.(map[string]interface{})
+			name := value["name"].(string)
+			label := exportLabel(name, labels)
+			if len(body.Blocks()) > 0 {
+				body.AppendNewline()
+			}
+			block := body.AppendNewBlock("resource", []string{resourceType, label})
+			for _, attribute := range kind.attributes {
+				switch v := value[attribute].(type) {
+				case string:
+					block.Body().SetAttributeValue(attribute, cty.StringVal(v))
+				case bool:
+					block.Body().SetAttributeValue(attribute, cty.BoolVal(v))
+				}
+			}
+			if imports {
+				body.AppendNewline()
+				block := body.AppendNewBlock("import", nil)
+				block.Body().SetAttributeTraversal("to", hcl.Traversal{
+					hcl.TraverseRoot{Name: resourceType},
+					hcl.TraverseAttr{Name: label},
+				})
+				block.Body().SetAttributeValue("id", cty.StringVal(name))
+			}
+		}
+	}
+	return file.Bytes()
+}
+
+// exportLabel returns a unique terraform resource name for the primitive with the given name
+func exportLabel(name string, used map[string]bool) string {
+	label := invalidLabelChars.ReplaceAllString(name, "_")
+	if label == "" || (label[0] >= '0' && label[0] <= '9') || label[0] == '-' {
+		label = "_" + label
+	}
+	unique := label
+	for i := 2; used[unique]; i++ {
+		unique = label + "_" + strconv.Itoa(i)
+	}
+	used[unique] = true
+	return unique
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	apipb "github.com/graphikDB/graphik/gen/grpc/go"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExportFlags(t *testing.T) {
+	arguments := Provider().(*schema.Provider).Schema
+	flags := flag.NewFlagSet(exportCommand, flag.ContinueOnError)
+	flags.SetOutput(ioutil.Discard)
+	providerConfig := exportFlags(flags, arguments)
+	for name := range arguments {
+		if flags.Lookup(name) == nil {
+			t.Errorf("provider argument %s has no flag", name)
+		}
+	}
+	err := flags.Parse([]string{
+		"-host", "localhost:7820",
+		"-insecure",
+		"-max_retries", "5",
+		"-retryable_codes", "Unavailable, Aborted",
+		"-grant_type", "client_credentials",
+		"-client_id", "terraform",
+		"-client_secret", "secret",
+		"-client_cert", "/etc/graphik/client.pem",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{
+		"host":            "localhost:7820",
+		"insecure":        true,
+		"max_retries":     5,
+		"retryable_codes": []interface{}{"Unavailable", "Aborted"},
+		"grant_type":      "client_credentials",
+		"client_id":       "terraform",
+		"client_secret":   "secret",
+		"client_cert":     "/etc/graphik/client.pem",
+	}
+	if config := providerConfig(); !reflect.DeepEqual(config, expected) {
+		t.Errorf("expected config %v, got: %v", expected, config)
+	}
+	stderr := &bytes.Buffer{}
+	if code := runExport([]string{"-host", "localhost:7820", "-request_timeout", "soon"}, ioutil.Discard, stderr); code != 2 || !strings.Contains(stderr.String(), "request_timeout") {
+		t.Errorf("expected an invalid request_timeout to exit with 2, got %d: %s", code, stderr)
+	}
+}
+
+func TestExportSchema(t *testing.T) {
+	client := newFakeClient()
+	client.seed()
+	client.schema.Indexes.Indexes = append(client.schema.Indexes.Indexes, &apipb.Index{Name: "1 priority", Gtype: "task", Expression: "true"})
+	out := exportSchema(client.schema, true)
+	if _, diags := hclwrite.ParseConfig(out, "export.tf", hcl.InitialPos); diags.HasErrors() {
+		t.Fatalf("exported invalid HCL: %s\n%s", diags, out)
+	}
+	for _, expected := range []string{
+		"resource \"graphik_index\" \"a\" {\n  name               = \"a\"\n  gtype              = \"task\"\n",
+		"import {\n  to = graphik_index.a\n  id = \"a\"\n}",
+		"resource \"graphik_index\" \"_1_priority\" {\n  name               = \"1 priority\"",
+		"import {\n  to = graphik_index._1_priority\n  id = \"1 priority\"\n}",
+		"  expression         = \"true\"\n  trigger            = \"{'updated_at': now()}\"",
+		"resource \"graphik_authorizer\" \"c\" {\n  name             = \"c\"\n  method           = \"/api.DatabaseService/GetSchema\"",
+		"import {\n  to = graphik_constraint.b\n  id = \"b\"\n}",
+	} {
+		if !strings.Contains(string(out), expected) {
+			t.Errorf("expected export to contain:\n%s\ngot:\n%s", expected, out)
+		}
+	}
+	if strings.Contains(string(exportSchema(client.schema, false)), "import {") {
+		t.Fatal("expected no import blocks")
+	}
+	if label := exportLabel("a", map[string]bool{"a": true, "a_2": true}); label != "a_3" {
+		t.Fatalf("expected a_3, got: %s", label)
+	}
+}
@@ -0,0 +1,475 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
+	apipb "github.com/graphikDB/graphik/gen/grpc/go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// fakeClient is an in-memory implementation of the graphik schema api
+type fakeClient struct {
+	mu     sync.Mutex
+	schema *apipb.Schema
+	// dropSets is the number of upcoming Set* calls that succeed without changing the schema,
+	// simulating a concurrent writer that overwrites the change. A negative value drops every call.
+	dropSets int
+	// me is the authenticated identity
+	me *apipb.Doc
+	// err is returned by Ping & Me, simulating a connection or authentication failure
+	err error
+	// schemaErrs are returned by the upcoming GetSchema calls, simulating transient failures
+	schemaErrs []error
+	// getSchemaCalls is the number of GetSchema calls
+	getSchemaCalls int
+	// docs are the docs of the graph keyed by gtype/gid
+	docs map[string]*apipb.Doc
+	// connections are the connections of the graph keyed by gtype/gid
+	connections map[string]*apipb.Connection
+	// gids is the number of gids generated for docs & connections created without one
+	gids int
+	// batchCalls is the number of PutDocs & DelDocs calls
+	batchCalls int
+	// batchErrs are returned by the upcoming PutDocs & DelDocs calls
+	batchErrs []error
+	// traverseFilter is the filter of the last Traverse call
+	traverseFilter *apipb.TraverseFilter
+	// getDocDeadline is the deadline of the context of the last GetDoc call
+	getDocDeadline time.Time
+}
+
+// batchErr returns the error of the current PutDocs or DelDocs call. The caller must hold f.mu.
+func (f *fakeClient) batchErr() error {
+	f.batchCalls++
+	if len(f.batchErrs) == 0 {
+		return nil
+	}
+	err := f.batchErrs[0]
+	f.batchErrs = f.batchErrs[1:]
+	return err
+}
+
+func newFakeClient() *fakeClient {
+	me, _ := structpb.NewStruct(map[string]interface{}{"email": "terraform@example.com"})
+	return &fakeClient{
+		me:          &apipb.Doc{Ref: &apipb.Ref{Gtype: "user", Gid: "terraform"}, Attributes: me},
+		docs:        map[string]*apipb.Doc{},
+		connections: map[string]*apipb.Connection{},
+		schema: &apipb.Schema{
+			Authorizers: &apipb.Authorizers{},
+			Constraints: &apipb.Constraints{},
+			Indexes:     &apipb.Indexes{},
+			Triggers:    &apipb.Triggers{},
+		},
+	}
+}
+
+// drop reports whether the current Set* call should be dropped. The caller must hold f.mu.
+func (f *fakeClient) drop() bool {
+	if f.dropSets == 0 {
+		return false
+	}
+	if f.dropSets > 0 {
+		f.dropSets--
+	}
+	return true
+}
+
+func (f *fakeClient) Ping(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*apipb.Pong, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &apipb.Pong{Message: "PONG"}, nil
+}
+
+func (f *fakeClient) Me(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*apipb.Doc, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return proto.Clone(f.me).(*apipb.Doc), nil
+}
+
+func (f *fakeClient) GetSchema(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*apipb.Schema, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.getSchemaCalls++
+	if len(f.schemaErrs) > 0 {
+		err := f.schemaErrs[0]
+		f.schemaErrs = f.schemaErrs[1:]
+		return nil, err
+	}
+	return proto.Clone(f.schema).(*apipb.Schema), nil
+}
+
+func (f *fakeClient) SetIndexes(ctx context.Context, in *apipb.Indexes, opts ...grpc.CallOption) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, v := range in.GetIndexes() {
+		if v == nil {
+			return errors.New("nil index")
+		}
+	}
+	if f.drop() {
+		return nil
+	}
+	f.schema.Indexes = proto.Clone(in).(*apipb.Indexes)
+	return nil
+}
+
+func (f *fakeClient) SetTriggers(ctx context.Context, in *apipb.Triggers, opts ...grpc.CallOption) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, v := range in.GetTriggers() {
+		if v == nil {
+			return errors.New("nil trigger")
+		}
+	}
+	if f.drop() {
+		return nil
+	}
+	f.schema.Triggers = proto.Clone(in).(*apipb.Triggers)
+	return nil
+}
+
+func (f *fakeClient) SetConstraints(ctx context.Context, in *apipb.Constraints, opts ...grpc.CallOption) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, v := range in.GetConstraints() {
+		if v == nil {
+			return errors.New("nil constraint")
+		}
+	}
+	if f.drop() {
+		return nil
+	}
+	f.schema.Constraints = proto.Clone(in).(*apipb.Constraints)
+	return nil
+}
+
+func (f *fakeClient) SetAuthorizers(ctx context.Context, in *apipb.Authorizers, opts ...grpc.CallOption) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, v := range in.GetAuthorizers() {
+		if v == nil {
+			return errors.New("nil authorizer")
+		}
+	}
+	if f.drop() {
+		return nil
+	}
+	f.schema.Authorizers = proto.Clone(in).(*apipb.Authorizers)
+	return nil
+}
+
+func (f *fakeClient) CreateDoc(ctx context.Context, in *apipb.DocConstructor, opts ...grpc.CallOption) (*apipb.Doc, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	ref := &apipb.Ref{Gtype: in.GetRef().GetGtype(), Gid: in.GetRef().GetGid()}
+	if ref.Gid == "" {
+		f.gids++
+		ref.Gid = fmt.Sprintf("generated-%d", f.gids)
+	}
+	if _, ok := f.docs[refID(ref)]; ok {
+		return nil, status.Errorf(codes.AlreadyExists, "%s already exists", refID(ref))
+	}
+	doc := &apipb.Doc{Ref: ref, Attributes: in.GetAttributes()}
+	f.docs[refID(ref)] = proto.Clone(doc).(*apipb.Doc)
+	return doc, nil
+}
+
+func (f *fakeClient) GetDoc(ctx context.Context, in *apipb.Ref, opts ...grpc.CallOption) (*apipb.Doc, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.getDocDeadline, _ = ctx.Deadline()
+	doc, ok := f.docs[refID(in)]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	return proto.Clone(doc).(*apipb.Doc), nil
+}
+
+// SearchDocs returns the docs of the type sorted by gid: expressions & sort fields other than ref.gid aren't evaluated
+func (f *fakeClient) SearchDocs(ctx context.Context, in *apipb.Filter, opts ...grpc.CallOption) (*apipb.Docs, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	docs := &apipb.Docs{}
+	for _, doc := range f.docs {
+		if doc.GetRef().GetGtype() == in.GetGtype() {
+			docs.Docs = append(docs.Docs, proto.Clone(doc).(*apipb.Doc))
+		}
+	}
+	if len(docs.GetDocs()) == 0 {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	docs.Sort("ref.gid")
+	if in.GetReverse() {
+		for i, j := 0, len(docs.Docs)-1; i < j; i, j = i+1, j-1 {
+			docs.Docs[i], docs.Docs[j] = docs.Docs[j], docs.Docs[i]
+		}
+	}
+	if uint64(len(docs.GetDocs())) > in.GetLimit() {
+		docs.Docs = docs.Docs[:in.GetLimit()]
+	}
+	return docs, nil
+}
+
+func (f *fakeClient) PutDoc(ctx context.Context, in *apipb.Doc, opts ...grpc.CallOption) (*apipb.Doc, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.docs[refID(in.GetRef())] = proto.Clone(in).(*apipb.Doc)
+	return in, nil
+}
+
+func (f *fakeClient) DelDoc(ctx context.Context, in *apipb.Ref, opts ...grpc.CallOption) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.docs[refID(in)]; !ok {
+		return status.Error(codes.NotFound, "not found")
+	}
+	delete(f.docs, refID(in))
+	return nil
+}
+
+func (f *fakeClient) PutDocs(ctx context.Context, in *apipb.Docs, opts ...grpc.CallOption) (*apipb.Docs, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.batchErr(); err != nil {
+		return nil, err
+	}
+	for _, doc := range in.GetDocs() {
+		f.docs[refID(doc.GetRef())] = proto.Clone(doc).(*apipb.Doc)
+	}
+	return in, nil
+}
+
+// fakeGids matches the quoted gids of the filters built by gidFilter
+var fakeGids = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+
+func (f *fakeClient) DelDocs(ctx context.Context, in *apipb.Filter, opts ...grpc.CallOption) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.batchErr(); err != nil {
+		return err
+	}
+	deleted := 0
+	for _, quoted := range fakeGids.FindAllString(in.GetExpression(), -1) {
+		gid, err := strconv.Unquote(quoted)
+		if err != nil {
+			return err
+		}
+		ref := refID(&apipb.Ref{Gtype: in.GetGtype(), Gid: gid})
+		if _, ok := f.docs[ref]; ok && uint64(deleted) < in.GetLimit() {
+			delete(f.docs, ref)
+			deleted++
+		}
+	}
+	if deleted == 0 {
+		return status.Error(codes.Unknown, "not found")
+	}
+	return nil
+}
+
+// Traverse follows the connections from the root breadth or depth first: expressions & sort aren't evaluated
+func (f *fakeClient) Traverse(ctx context.Context, in *apipb.TraverseFilter, opts ...grpc.CallOption) (*apipb.Traversals, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.traverseFilter = proto.Clone(in).(*apipb.TraverseFilter)
+	if _, ok := f.docs[refID(in.GetRoot())]; !ok {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	var connections []*apipb.Connection
+	for _, connection := range f.connections {
+		connections = append(connections, connection)
+	}
+	sort.Slice(connections, func(i, j int) bool {
+		return refID(connections[i].GetRef()) < refID(connections[j].GetRef())
+	})
+	traversals := &apipb.Traversals{}
+	visited := map[string]bool{}
+	pending := []*apipb.Traversal{{Doc: f.docs[refID(in.GetRoot())], TraversalPath: []*apipb.Ref{}}}
+	for len(pending) > 0 && uint64(len(traversals.GetTraversals())) < in.GetLimit() && uint64(len(visited)) < in.GetMaxHops() {
+		var traversal *apipb.Traversal
+		if in.GetAlgorithm() == apipb.Algorithm_DFS {
+			traversal, pending = pending[len(pending)-1], pending[:len(pending)-1]
+		} else {
+			traversal, pending = pending[0], pending[1:]
+		}
+		ref := refID(traversal.GetDoc().GetRef())
+		if visited[ref] {
+			continue
+		}
+		visited[ref] = true
+		traversal.Hops = uint64(len(visited))
+		traversals.Traversals = append(traversals.Traversals, proto.Clone(traversal).(*apipb.Traversal))
+		if traversal.GetDepth() >= in.GetMaxDepth() {
+			continue
+		}
+		var next []*apipb.Traversal
+		for _, connection := range connections {
+			to := connection.GetTo()
+			if refID(connection.GetFrom()) != ref {
+				if connection.GetDirected() || refID(to) != ref {
+					continue
+				}
+				to = connection.GetFrom()
+			}
+			next = append(next, &apipb.Traversal{
+				Doc:           f.docs[refID(to)],
+				TraversalPath: append(append([]*apipb.Ref{}, traversal.GetTraversalPath()...), traversal.GetDoc().GetRef()),
+				Depth:         traversal.GetDepth() + 1,
+			})
+		}
+		if in.GetAlgorithm() == apipb.Algorithm_DFS {
+			// the first connection is followed first
+			for i, j := 0, len(next)-1; i < j; i, j = i+1, j-1 {
+				next[i], next[j] = next[j], next[i]
+			}
+		}
+		pending = append(pending, next...)
+	}
+	return traversals, nil
+}
+
+func (f *fakeClient) CreateConnection(ctx context.Context, in *apipb.ConnectionConstructor, opts ...grpc.CallOption) (*apipb.Connection, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, ref := range []*apipb.Ref{in.GetFrom(), in.GetTo()} {
+		if _, ok := f.docs[refID(ref)]; !ok {
+			return nil, status.Errorf(codes.NotFound, "%s not found", refID(ref))
+		}
+	}
+	ref := &apipb.Ref{Gtype: in.GetRef().GetGtype(), Gid: in.GetRef().GetGid()}
+	if ref.Gid == "" {
+		f.gids++
+		ref.Gid = fmt.Sprintf("generated-%d", f.gids)
+	}
+	if _, ok := f.connections[refID(ref)]; ok {
+		return nil, status.Errorf(codes.AlreadyExists, "%s already exists", refID(ref))
+	}
+	connection := &apipb.Connection{
+		Ref:        ref,
+		Attributes: in.GetAttributes(),
+		Directed:   in.GetDirected(),
+		From:       in.GetFrom(),
+		To:         in.GetTo(),
+	}
+	f.connections[refID(ref)] = proto.Clone(connection).(*apipb.Connection)
+	return connection, nil
+}
+
+func (f *fakeClient) GetConnection(ctx context.Context, in *apipb.Ref, opts ...grpc.CallOption) (*apipb.Connection, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	connection, ok := f.connections[refID(in)]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "not found")
+	}
+	return proto.Clone(connection).(*apipb.Connection), nil
+}
+
+func (f *fakeClient) PutConnection(ctx context.Context, in *apipb.Connection, opts ...grpc.CallOption) (*apipb.Connection, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.connections[refID(in.GetRef())] = proto.Clone(in).(*apipb.Connection)
+	return in, nil
+}
+
+func (f *fakeClient) DelConnection(ctx context.Context, in *apipb.Ref, opts ...grpc.CallOption) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.connections[refID(in)]; !ok {
+		return status.Error(codes.NotFound, "not found")
+	}
+	delete(f.connections, refID(in))
+	return nil
+}
+
+// seed populates the fake with three primitives of every kind named a, b & c
+func (f *fakeClient) seed() {
+	for _, name := range []string{"a", "b", "c"} {
+		f.schema.Indexes.Indexes = append(f.schema.Indexes.Indexes, &apipb.Index{
+			Name:       name,
+			Gtype:      "task",
+			Expression: "true",
+			TargetDocs: true,
+		})
+		f.schema.Triggers.Triggers = append(f.schema.Triggers.Triggers, &apipb.Trigger{
+			Name:       name,
+			Gtype:      "task",
+			Trigger:    triggerArrow("true", "{'updated_at': now()}"),
+			TargetDocs: true,
+		})
+		f.schema.Constraints.Constraints = append(f.schema.Constraints.Constraints, &apipb.Constraint{
+			Name:       name,
+			Gtype:      "task",
+			Expression: "true",
+			TargetDocs: true,
+		})
+		f.schema.Authorizers.Authorizers = append(f.schema.Authorizers.Authorizers, &apipb.Authorizer{
+			Name:           name,
+			Method:         "/api.DatabaseService/GetSchema",
+			Expression:     "true",
+			TargetRequests: true,
+		})
+	}
+}
+
+// names returns the names of the primitives of the given resource type held by the fake
+func (f *fakeClient) names(resourceType string) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var names []string
+	switch resourceType {
+	case "graphik_index":
+		for _, v := range f.schema.GetIndexes().GetIndexes() {
+			names = append(names, v.GetName())
+		}
+	case "graphik_trigger":
+		for _, v := range f.schema.GetTriggers().GetTriggers() {
+			names = append(names, v.GetName())
+		}
+	case "graphik_constraint":
+		for _, v := range f.schema.GetConstraints().GetConstraints() {
+			names = append(names, v.GetName())
+		}
+	case "graphik_authorizer":
+		for _, v := range f.schema.GetAuthorizers().GetAuthorizers() {
+			names = append(names, v.GetName())
+		}
+	}
+	return names
+}
+
+// testProviders returns the graphik provider configured against the given fake
+func testProviders(client *fakeClient) map[string]terraform.ResourceProvider {
+	provider := Provider().(*schema.Provider)
+	provider.ConfigureFunc = func(data *schema.ResourceData) (interface{}, error) {
+		meta := newProviderMeta(client)
+		meta.requestTimeout, _ = time.ParseDuration(data.Get("request_timeout").(string))
+		return meta, configureRetries(meta, data)
+	}
+	return map[string]terraform.ResourceProvider{
+		"graphik": provider,
+	}
+}
+
+const testProviderConfig = `
+provider "graphik" {
+  host         = "localhost:7820"
+  access_token = "token"
+  open_id      = "http://localhost/.well-known/openid-configuration"
+}
+`
@@ -4,11 +4,18 @@ go 1.15
 
 require (
 	github.com/golang/protobuf v1.4.3
+	github.com/google/cel-go v0.6.1-0.20201210004405-3ea8bd382b11
 	github.com/graphikDB/graphik v1.2.0
+	github.com/graphikDB/trigger v0.0.14
+	github.com/hashicorp/hcl/v2 v2.3.0
 	github.com/hashicorp/terraform-plugin-sdk v1.16.0
 	github.com/joho/godotenv v1.3.0 // indirect
 	github.com/mitchellh/go-homedir v1.1.0
 	github.com/pkg/errors v0.9.1
 	github.com/spf13/viper v1.7.1
+	github.com/zclconf/go-cty v1.2.1
 	golang.org/x/oauth2 v0.0.0-20200107190931-bf48bf16ab8d
+	google.golang.org/genproto v0.0.0-20201102152239-715cce707fb0
+	google.golang.org/grpc v1.33.2
+	google.golang.org/protobuf v1.25.0
 )
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/pkg/errors"
+)
+
+// importPrimitive returns the importer of a resource managing a single primitive of the given kind.
+// The import id is the name of the primitive, which must exist on the server.
+func importPrimitive(b bulkKind) *schema.ResourceImporter {
+	return &schema.ResourceImporter{
+		State: func(data *schema.ResourceData, i interface{}) ([]*schema.ResourceData, error) {
+			if data.Id() == "*" {
+				// importing every primitive into one resource address would leave states terraform's config doesn't
+				// declare, so the next plan would destroy them
+				return nil, errors.Errorf("importing every %s at once isn't supported: run terraform-provider-graphik export to generate a resource & import block per %s", b.block, b.block)
+			}
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.requestTimeout)
+			defer cancel()
+			scheme, err := meta.getSchema(ctx)
+			if err != nil {
+				return nil, err
+			}
+			value, ok := primitivesByName(b.flatten(scheme))[data.Id()]
+			if !ok {
+				return nil, errors.Errorf("%s %s not found", b.block, data.Id())
+			}
+			return []*schema.ResourceData{data}, setPrimitive(data, value)
+		},
+	}
+}
+
+// importBulk returns the importer of the authoritative resource owning every primitive of the given kind.
+// The import id must be the kind, ex: terraform import graphik_indexes.all indexes
+func importBulk(b bulkKind) *schema.ResourceImporter {
+	return &schema.ResourceImporter{
+		State: func(data *schema.ResourceData, i interface{}) ([]*schema.ResourceData, error) {
+			if data.Id() != string(b.kind) {
+				return nil, errors.Errorf("unexpected import id %q: the %s are imported with the id %s", data.Id(), b.kind, b.kind)
+			}
+			return []*schema.ResourceData{data}, nil
+		},
+	}
+}
+
+// setPrimitive sets the id & attributes of the resource from a flattened primitive
+func setPrimitive(data *schema.ResourceData, value map[string]interface{}) error {
+	for k, v := range value {
+		if err := data.Set(k, v); err != nil {
+			return err
+		}
+	}
+	data.SetId(value["name"].(string))
+	return nil
+}
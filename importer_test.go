@@ -0,0 +1,42 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"regexp"
+	"testing"
+)
+
+func TestAccIndex_import(t *testing.T) {
+	client := newFakeClient()
+	client.seed()
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testProviders(client),
+		Steps: []resource.TestStep{
+			{
+				Config: testIndexConfig,
+			},
+			{
+				ResourceName:      "graphik_index.low_priority",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:  "graphik_index.low_priority",
+				ImportState:   true,
+				ImportStateId: "missing",
+				ExpectError:   regexp.MustCompile("index missing not found"),
+			},
+			{
+				ResourceName:  "graphik_index.low_priority",
+				ImportState:   true,
+				ImportStateId: "*",
+				ExpectError:   regexp.MustCompile("run terraform-provider-graphik export"),
+			},
+			{
+				// the rejected import leaves no undeclared states behind for the next plan to destroy
+				Config:   testIndexConfig,
+				PlanOnly: true,
+			},
+		},
+	})
+}
@@ -2,7 +2,8 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"fmt"
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/empty"
 	apipb "github.com/graphikDB/graphik/gen/grpc/go"
 	"github.com/graphikDB/graphik/graphik-client-go"
@@ -14,872 +15,936 @@ import (
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 	"golang.org/x/oauth2"
-	"net/http"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"net/url"
 	"os"
+	"strings"
 	"time"
 )
 
+// graphikClient is the subset of the graphik client used by the provider
+type graphikClient interface {
+	Ping(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*apipb.Pong, error)
+	Me(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*apipb.Doc, error)
+	GetSchema(ctx context.Context, in *empty.Empty, opts ...grpc.CallOption) (*apipb.Schema, error)
+	SetIndexes(ctx context.Context, in *apipb.Indexes, opts ...grpc.CallOption) error
+	SetTriggers(ctx context.Context, in *apipb.Triggers, opts ...grpc.CallOption) error
+	SetConstraints(ctx context.Context, in *apipb.Constraints, opts ...grpc.CallOption) error
+	SetAuthorizers(ctx context.Context, in *apipb.Authorizers, opts ...grpc.CallOption) error
+	CreateDoc(ctx context.Context, in *apipb.DocConstructor, opts ...grpc.CallOption) (*apipb.Doc, error)
+	GetDoc(ctx context.Context, in *apipb.Ref, opts ...grpc.CallOption) (*apipb.Doc, error)
+	SearchDocs(ctx context.Context, in *apipb.Filter, opts ...grpc.CallOption) (*apipb.Docs, error)
+	Traverse(ctx context.Context, in *apipb.TraverseFilter, opts ...grpc.CallOption) (*apipb.Traversals, error)
+	PutDoc(ctx context.Context, in *apipb.Doc, opts ...grpc.CallOption) (*apipb.Doc, error)
+	DelDoc(ctx context.Context, in *apipb.Ref, opts ...grpc.CallOption) error
+	PutDocs(ctx context.Context, in *apipb.Docs, opts ...grpc.CallOption) (*apipb.Docs, error)
+	DelDocs(ctx context.Context, in *apipb.Filter, opts ...grpc.CallOption) error
+	CreateConnection(ctx context.Context, in *apipb.ConnectionConstructor, opts ...grpc.CallOption) (*apipb.Connection, error)
+	GetConnection(ctx context.Context, in *apipb.Ref, opts ...grpc.CallOption) (*apipb.Connection, error)
+	PutConnection(ctx context.Context, in *apipb.Connection, opts ...grpc.CallOption) (*apipb.Connection, error)
+	DelConnection(ctx context.Context, in *apipb.Ref, opts ...grpc.CallOption) error
+}
+
 func main() {
 	initConfig()
-	plugin.Serve(&plugin.ServeOpts{ProviderFunc: func() terraform.ResourceProvider {
-		primarySchema := map[string]*schema.Schema{
-			"host": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "host/endpoint of graphikDB instance",
-				DefaultFunc: func() (interface{}, error) {
-					return viper.GetString("host"), nil
-				},
+	if len(os.Args) > 1 && os.Args[1] == exportCommand {
+		os.Exit(runExport(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	plugin.Serve(&plugin.ServeOpts{ProviderFunc: Provider})
+}
+
+// Provider returns the graphik terraform provider
+func Provider() terraform.ResourceProvider {
+	primarySchema := map[string]*schema.Schema{
+		"host": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "host/endpoint of graphikDB instance. defaults to GRAPHIK_HOST & then host in the graphikctl config",
+		},
+		"access_token": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Sensitive:   true,
+			Description: "oidc access token from identity provider. defaults to GRAPHIK_ACCESS_TOKEN & then auth.access_token in the graphikctl config",
+		},
+		"open_id": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "open id connect metadata endpoint. required by grant_type & used to verify access_token when set. defaults to GRAPHIK_OPEN_ID & then auth.open_id in the graphikctl config",
+		},
+		"profile": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "named profile of the graphikctl config (profiles.<name>) to read host, auth.access_token & auth.open_id from instead of the top level of the config",
+			DefaultFunc: schema.EnvDefaultFunc("GRAPHIK_PROFILE", ""),
+		},
+		"request_timeout": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      defaultRequestTimeout.String(),
+			Description:  "timeout of each resource operation & data source read that doesn't set its own timeouts (ex: 30s, 2m)",
+			ValidateFunc: validateDuration,
+		},
+		"max_retries": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      defaultMaxRetries,
+			Description:  "number of times a call to graphik failing with one of retryable_codes is retried",
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"retry_backoff_min": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      defaultRetryBackoffMin.String(),
+			Description:  "delay before the first retry, doubled on every following retry (ex: 250ms)",
+			ValidateFunc: validateDuration,
+		},
+		"retry_backoff_max": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      defaultRetryBackoffMax.String(),
+			Description:  "upper bound of the delay between retries (ex: 5s)",
+			ValidateFunc: validateDuration,
+		},
+		"retryable_codes": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "gRPC status codes of the errors that are retried (ex: Unavailable, Aborted). defaults to Unavailable, ResourceExhausted & Aborted",
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringInSlice(grpcCodeNames(), false),
 			},
-			"access_token": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "oidc access token from identity provider",
-				DefaultFunc: func() (interface{}, error) {
-					return viper.GetString("auth.access_token"), nil
+		},
+		"strict_concurrency": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "fail schema updates that were overwritten by a concurrent writer instead of retrying them",
+		},
+	}
+	for k, v := range authSchema() {
+		primarySchema[k] = v
+	}
+	for k, v := range tlsSchema() {
+		primarySchema[k] = v
+	}
+	indexSchema := map[string]*schema.Schema{
+		"name": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "unique name of the index",
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+		"gtype": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "replace me",
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+		"expression": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "replace me",
+			ValidateFunc: validateCEL,
+		},
+		"target_docs": {
+			Type:        schema.TypeBool,
+			Required:    true,
+			Description: "replace me",
+		},
+		"target_connections": {
+			Type:        schema.TypeBool,
+			Required:    true,
+			Description: "replace me",
+		},
+	}
+	triggerSchema := map[string]*schema.Schema{
+		"name": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "unique name of the index",
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+		"gtype": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "replace me",
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+		"expression": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "boolean CEL expression that gates whether the trigger is applied to a doc/connection",
+			ValidateFunc: validateCEL,
+		},
+		"trigger": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "replace me",
+			ValidateFunc: validateCELMap,
+		},
+		"target_docs": {
+			Type:        schema.TypeBool,
+			Required:    true,
+			Description: "replace me",
+		},
+		"target_connections": {
+			Type:        schema.TypeBool,
+			Required:    true,
+			Description: "replace me",
+		},
+	}
+	constraintSchema := map[string]*schema.Schema{
+		"name": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "unique name of the index",
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+		"gtype": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "replace me",
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+		"expression": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "replace me",
+			ValidateFunc: validateCEL,
+		},
+		"target_docs": {
+			Type:        schema.TypeBool,
+			Required:    true,
+			Description: "replace me",
+		},
+		"target_connections": {
+			Type:        schema.TypeBool,
+			Required:    true,
+			Description: "replace me",
+		},
+	}
+	authorizerSchema := map[string]*schema.Schema{
+		"name": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "unique name of the index",
+			ValidateFunc: validation.StringIsNotEmpty,
+		},
+		"method": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "the full name of the gRPC method that invokes the authorizer (ex: /api.DatabaseService/CreateDoc)",
+			ValidateFunc: validateMethod,
+		},
+		"expression": {
+			Type:         schema.TypeString,
+			Required:     true,
+			Description:  "replace me",
+			ValidateFunc: validateCEL,
+		},
+		"target_requests": {
+			Type:        schema.TypeBool,
+			Required:    true,
+			Description: "replace me",
+		},
+		"target_responses": {
+			Type:        schema.TypeBool,
+			Required:    true,
+			Description: "replace me",
+		},
+	}
+	return &schema.Provider{
+		Schema: primarySchema,
+		DataSourcesMap: map[string]*schema.Resource{
+			"graphik_schema":     dataSourceSchema(),
+			"graphik_index":      dataSourceIndex(),
+			"graphik_trigger":    dataSourceTrigger(),
+			"graphik_constraint": dataSourceConstraint(),
+			"graphik_authorizer": dataSourceAuthorizer(),
+			"graphik_me":         dataSourceMe(),
+			"graphik_docs":       dataSourceDocs(),
+			"graphik_doc":        dataSourceDoc(),
+			"graphik_traversal":  dataSourceTraversal(),
+		},
+		ResourcesMap: map[string]*schema.Resource{
+			"graphik_index": {
+				Schema: indexSchema,
+				Create: func(data *schema.ResourceData, i interface{}) error {
+					meta := i.(*providerMeta)
+					ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutCreate))
+					defer cancel()
+					index := indexFromData(data)
+					err := meta.updateSchema(ctx, indexesKind, func(scheme *apipb.Schema) {
+						scheme.Indexes = &apipb.Indexes{Indexes: upsertIndex(index, scheme.GetIndexes().GetIndexes())}
+					}, func(scheme *apipb.Schema) bool {
+						return proto.Equal(findIndex(index.GetName(), scheme.GetIndexes().GetIndexes()), index)
+					})
+					if err != nil {
+						return err
+					}
+					data.SetId(index.GetName())
+					return nil
 				},
-			},
-			"open_id": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "open id connect metadata endpoint",
-				DefaultFunc: func() (interface{}, error) {
-					return viper.GetString("auth.open_id"), nil
+				Read: func(data *schema.ResourceData, i interface{}) error {
+					meta := i.(*providerMeta)
+					ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutRead))
+					defer cancel()
+					scheme, err := meta.getSchema(ctx)
+					if err != nil {
+						return err
+					}
+					index := findIndex(data.Id(), scheme.GetIndexes().GetIndexes())
+					if index == nil {
+						// the index was removed outside of terraform
+						data.SetId("")
+						return nil
+					}
+					return setIndexData(data, index)
 				},
+				Update: func(data *schema.ResourceData, i interface{}) error {
+					meta := i.(*providerMeta)
+					ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutUpdate))
+					defer cancel()
+					index := indexFromData(data)
+					err := meta.updateSchema(ctx, indexesKind, func(scheme *apipb.Schema) {
+						scheme.Indexes = &apipb.Indexes{Indexes: upsertIndex(index, scheme.GetIndexes().GetIndexes())}
+					}, func(scheme *apipb.Schema) bool {
+						return proto.Equal(findIndex(index.GetName(), scheme.GetIndexes().GetIndexes()), index)
+					})
+					if err != nil {
+						return err
+					}
+					data.SetId(index.GetName())
+					return nil
+				},
+				Delete: func(data *schema.ResourceData, i interface{}) error {
+					meta := i.(*providerMeta)
+					ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutDelete))
+					defer cancel()
+					return meta.updateSchema(ctx, indexesKind, func(scheme *apipb.Schema) {
+						scheme.Indexes = &apipb.Indexes{Indexes: removeIndex(data.Id(), scheme.GetIndexes().GetIndexes())}
+					}, func(scheme *apipb.Schema) bool {
+						return findIndex(data.Id(), scheme.GetIndexes().GetIndexes()) == nil
+					})
+				},
+				CustomizeDiff:      nil,
+				Importer:           importPrimitive(bulkIndexes),
+				DeprecationMessage: "",
+				Timeouts:           resourceTimeouts(),
+				Description:        "a graph primitive used for fast lookups of docs/connections that pass a boolean CEL expression",
 			},
-		}
-		indexSchema := map[string]*schema.Schema{
-			"name": {
-				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "unique name of the index",
-				ValidateFunc: validation.StringIsNotEmpty,
-			},
-			"gtype": {
-				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "replace me",
-				ValidateFunc: validation.StringIsNotEmpty,
-			},
-			"expression": {
-				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "replace me",
-				ValidateFunc: validation.StringIsNotEmpty,
-			},
-			"target_docs": {
-				Type:        schema.TypeBool,
-				Required:    true,
-				Description: "replace me",
-			},
-			"target_connections": {
-				Type:        schema.TypeBool,
-				Required:    true,
-				Description: "replace me",
-			},
-		}
-		triggerSchema := map[string]*schema.Schema{
-			"name": {
-				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "unique name of the index",
-				ValidateFunc: validation.StringIsNotEmpty,
-			},
-			"gtype": {
-				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "replace me",
-				ValidateFunc: validation.StringIsNotEmpty,
-			},
-			"trigger": {
-				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "replace me",
-				ValidateFunc: validation.StringIsNotEmpty,
-			},
-			"target_docs": {
-				Type:        schema.TypeBool,
-				Required:    true,
-				Description: "replace me",
-			},
-			"target_connections": {
-				Type:        schema.TypeBool,
-				Required:    true,
-				Description: "replace me",
-			},
-		}
-		constraintSchema := map[string]*schema.Schema{
-			"name": {
-				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "unique name of the index",
-				ValidateFunc: validation.StringIsNotEmpty,
-			},
-			"gtype": {
-				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "replace me",
-				ValidateFunc: validation.StringIsNotEmpty,
-			},
-			"expression": {
-				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "replace me",
-				ValidateFunc: validation.StringIsNotEmpty,
-			},
-			"target_docs": {
-				Type:        schema.TypeBool,
-				Required:    true,
-				Description: "replace me",
-			},
-			"target_connections": {
-				Type:        schema.TypeBool,
-				Required:    true,
-				Description: "replace me",
-			},
-		}
-		authorizerSchema := map[string]*schema.Schema{
-			"name": {
-				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "unique name of the index",
-				ValidateFunc: validation.StringIsNotEmpty,
-			},
-			"method": {
-				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "replace me",
-				ValidateFunc: validation.StringIsNotEmpty,
-			},
-			"expression": {
-				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "replace me",
-				ValidateFunc: validation.StringIsNotEmpty,
-			},
-			"target_requests": {
-				Type:        schema.TypeBool,
-				Required:    true,
-				Description: "replace me",
-			},
-			"target_responses": {
-				Type:        schema.TypeBool,
-				Required:    true,
-				Description: "replace me",
-			},
-		}
-		return &schema.Provider{
-			Schema: primarySchema,
-			ResourcesMap: map[string]*schema.Resource{
-				"graphik_index": {
-					Schema: indexSchema,
-					Create: func(data *schema.ResourceData, i interface{}) error {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						client := i.(*graphik.Client)
-						scheme, err := client.GetSchema(ctx, &empty.Empty{})
-						if err != nil {
-							return err
-						}
-						values := scheme.GetIndexes().GetIndexes()
-						data.SetId(data.Get("name").(string))
-						var has = false
-						for i, a := range values {
-							if a.GetName() == data.Get("name") {
-								has = true
-								values[i] = &apipb.Index{
-									Name:              data.Get("name").(string),
-									Gtype:             data.Get("gtype").(string),
-									Expression:        data.Get("expression").(string),
-									TargetDocs:        data.Get("target_docs").(bool),
-									TargetConnections: data.Get("target_connections").(bool),
-								}
-							}
-						}
-
-						if !has {
-							values = append(values, &apipb.Index{
-								Name:              data.Get("name").(string),
-								Gtype:             data.Get("gtype").(string),
-								Expression:        data.Get("expression").(string),
-								TargetDocs:        data.Get("target_docs").(bool),
-								TargetConnections: data.Get("target_connections").(bool),
-							})
-						}
-						if err := client.SetIndexes(ctx, &apipb.Indexes{Indexes: values}); err != nil {
-							return err
-						}
-						return nil
-					},
-					Read: func(data *schema.ResourceData, i interface{}) error {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						client := i.(*graphik.Client)
-						scheme, err := client.GetSchema(ctx, &empty.Empty{})
-						if err != nil {
-							return err
-						}
-						id := data.Id()
-						for _, a := range scheme.GetIndexes().GetIndexes() {
-							if a.GetName() == id {
-								if err := data.Set("name", a.GetName()); err != nil {
-									return err
-								}
-								if err := data.Set("gtype", a.GetGtype()); err != nil {
-									return err
-								}
-								if err := data.Set("expression", a.GetExpression()); err != nil {
-									return err
-								}
-								if err := data.Set("target_connections", a.GetTargetConnections()); err != nil {
-									return err
-								}
-								if err := data.Set("target_docs", a.GetTargetDocs()); err != nil {
-									return err
-								}
-							}
-						}
-						return nil
+			"graphik_trigger": {
+				Schema:        triggerSchema,
+				SchemaVersion: 1,
+				MigrateState:  nil,
+				StateUpgraders: []schema.StateUpgrader{
+					{
+						Version: 0,
+						Type:    triggerResourceV0().CoreConfigSchema().ImpliedType(),
+						Upgrade: upgradeTriggerStateV0,
 					},
-					Update: func(data *schema.ResourceData, i interface{}) error {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						client := i.(*graphik.Client)
-						scheme, err := client.GetSchema(ctx, &empty.Empty{})
-						if err != nil {
-							return err
-						}
-						values := scheme.GetIndexes().GetIndexes()
-						data.SetId(data.Get("name").(string))
-						var has = false
-						for i, a := range values {
-							if a.GetName() == data.Get("name") {
-								has = true
-								values[i] = &apipb.Index{
-									Name:              data.Get("name").(string),
-									Gtype:             data.Get("gtype").(string),
-									Expression:        data.Get("expression").(string),
-									TargetDocs:        data.Get("target_docs").(bool),
-									TargetConnections: data.Get("target_connections").(bool),
-								}
-							}
-						}
-
-						if !has {
-							values = append(values, &apipb.Index{
-								Name:              data.Get("name").(string),
-								Gtype:             data.Get("gtype").(string),
-								Expression:        data.Get("expression").(string),
-								TargetDocs:        data.Get("target_docs").(bool),
-								TargetConnections: data.Get("target_connections").(bool),
-							})
-						}
-						if err := client.SetIndexes(ctx, &apipb.Indexes{Indexes: values}); err != nil {
-							return err
-						}
-						return nil
-					},
-					Delete: func(data *schema.ResourceData, i interface{}) error {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						client := i.(*graphik.Client)
-						scheme, err := client.GetSchema(ctx, &empty.Empty{})
-						if err != nil {
-							return err
-						}
-						indexes := scheme.GetIndexes().GetIndexes()
-						var index = -1
-						for i, a := range indexes {
-							if a.GetName() == data.Get("name") {
-								index = i
-								indexes[i] = &apipb.Index{
-									Name:              data.Get("name").(string),
-									Gtype:             data.Get("gtype").(string),
-									Expression:        data.Get("expression").(string),
-									TargetDocs:        data.Get("target_docs").(bool),
-									TargetConnections: data.Get("target_connections").(bool),
-								}
-							}
-						}
-						if index >= 0 {
-							removeIndex(index, indexes)
-							if err := client.SetIndexes(ctx, &apipb.Indexes{Indexes: indexes}); err != nil {
-								return err
-							}
-						}
-						return nil
-					},
-					Exists: func(data *schema.ResourceData, i interface{}) (bool, error) {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						client := i.(*graphik.Client)
-						scheme, err := client.GetSchema(ctx, &empty.Empty{})
-						if err != nil {
-							return false, err
-						}
-						values := scheme.GetIndexes()
-						var has = false
-						for _, a := range values.GetIndexes() {
-							if a.GetName() == data.Id() {
-								has = true
-							}
-						}
-						return has, nil
-					},
-					CustomizeDiff: nil,
-					Importer: &schema.ResourceImporter{
-						State: schema.ImportStatePassthrough,
-					},
-					DeprecationMessage: "",
-					Timeouts:           nil,
-					Description:        "a graph primitive used for fast lookups of docs/connections that pass a boolean CEL expression",
 				},
-				"graphik_trigger": {
-					Schema:         triggerSchema,
-					SchemaVersion:  0,
-					MigrateState:   nil,
-					StateUpgraders: nil,
-					Create: func(data *schema.ResourceData, i interface{}) error {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						client := i.(*graphik.Client)
-						scheme, err := client.GetSchema(ctx, &empty.Empty{})
-						if err != nil {
-							return err
-						}
-						values := scheme.GetTriggers().GetTriggers()
-						data.SetId(data.Get("name").(string))
-						var has = false
-						for i, a := range values {
-							if a.GetName() == data.Get("name") {
-								has = true
-								values[i] = &apipb.Trigger{
-									Name:              data.Get("name").(string),
-									Gtype:             data.Get("gtype").(string),
-									Trigger:           data.Get("trigger").(string),
-									TargetDocs:        data.Get("target_docs").(bool),
-									TargetConnections: data.Get("target_connections").(bool),
-								}
-							}
-						}
-
-						if !has {
-							values = append(values, &apipb.Trigger{
-								Name:              data.Get("name").(string),
-								Gtype:             data.Get("gtype").(string),
-								Trigger:           data.Get("trigger").(string),
-								TargetDocs:        data.Get("target_docs").(bool),
-								TargetConnections: data.Get("target_connections").(bool),
-							})
-						}
-						if err := client.SetTriggers(ctx, &apipb.Triggers{Triggers: values}); err != nil {
-							return err
-						}
-						return nil
-					},
-					Read: func(data *schema.ResourceData, i interface{}) error {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						client := i.(*graphik.Client)
-						scheme, err := client.GetSchema(ctx, &empty.Empty{})
-						if err != nil {
-							return err
-						}
-						id := data.Id()
-						for _, a := range scheme.GetTriggers().GetTriggers() {
-							if a.GetName() == id {
-								if err := data.Set("name", a.GetName()); err != nil {
-									return err
-								}
-								if err := data.Set("gtype", a.GetGtype()); err != nil {
-									return err
-								}
-								if err := data.Set("trigger", a.GetTrigger()); err != nil {
-									return err
-								}
-								if err := data.Set("target_connections", a.GetTargetConnections()); err != nil {
-									return err
-								}
-								if err := data.Set("target_docs", a.GetTargetDocs()); err != nil {
-									return err
-								}
-							}
-						}
-						return nil
-					},
-					Update: func(data *schema.ResourceData, i interface{}) error {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						client := i.(*graphik.Client)
-						scheme, err := client.GetSchema(ctx, &empty.Empty{})
-						if err != nil {
-							return err
-						}
-						values := scheme.GetTriggers().GetTriggers()
-						data.SetId(data.Get("name").(string))
-						var has = false
-						for i, a := range values {
-							if a.GetName() == data.Get("name") {
-								has = true
-								values[i] = &apipb.Trigger{
-									Name:              data.Get("name").(string),
-									Gtype:             data.Get("gtype").(string),
-									Trigger:           data.Get("trigger").(string),
-									TargetDocs:        data.Get("target_docs").(bool),
-									TargetConnections: data.Get("target_connections").(bool),
-								}
-							}
-						}
-
-						if !has {
-							values = append(values, &apipb.Trigger{
-								Name:              data.Get("name").(string),
-								Gtype:             data.Get("gtype").(string),
-								Trigger:           data.Get("trigger").(string),
-								TargetDocs:        data.Get("target_docs").(bool),
-								TargetConnections: data.Get("target_connections").(bool),
-							})
-						}
-						if err := client.SetTriggers(ctx, &apipb.Triggers{Triggers: values}); err != nil {
-							return err
-						}
-						return nil
-					},
-					Delete: func(data *schema.ResourceData, i interface{}) error {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						client := i.(*graphik.Client)
-						scheme, err := client.GetSchema(ctx, &empty.Empty{})
-						if err != nil {
-							return err
-						}
-						triggers := scheme.GetTriggers().GetTriggers()
-						var index = -1
-						for i, a := range triggers {
-							if a.GetName() == data.Get("name") {
-								index = i
-								triggers[i] = &apipb.Trigger{
-									Name:              data.Get("name").(string),
-									Gtype:             data.Get("gtype").(string),
-									Trigger:           data.Get("trigger").(string),
-									TargetDocs:        data.Get("target_docs").(bool),
-									TargetConnections: data.Get("target_connections").(bool),
-								}
-							}
-						}
-						if index >= 0 {
-							removeTrigger(index, triggers)
-							if err := client.SetTriggers(ctx, &apipb.Triggers{Triggers: triggers}); err != nil {
-								return err
-							}
-						}
-						return nil
-					},
-					Exists: func(data *schema.ResourceData, i interface{}) (bool, error) {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						client := i.(*graphik.Client)
-						scheme, err := client.GetSchema(ctx, &empty.Empty{})
-						if err != nil {
-							return false, err
-						}
-						values := scheme.GetTriggers()
-						var has = false
-						for _, a := range values.GetTriggers() {
-							if a.GetName() == data.Id() {
-								has = true
-							}
-						}
-						return has, nil
-					},
-					CustomizeDiff: nil,
-					Importer: &schema.ResourceImporter{
-						State: schema.ImportStatePassthrough,
-					},
-					DeprecationMessage: "",
-					Timeouts:           nil,
-					Description:        "used to automatically mutate the attributes of documents/connections before they are commited to the database",
+				Create: func(data *schema.ResourceData, i interface{}) error {
+					meta := i.(*providerMeta)
+					ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutCreate))
+					defer cancel()
+					trigger := triggerFromData(data)
+					err := meta.updateSchema(ctx, triggersKind, func(scheme *apipb.Schema) {
+						scheme.Triggers = &apipb.Triggers{Triggers: upsertTrigger(trigger, scheme.GetTriggers().GetTriggers())}
+					}, func(scheme *apipb.Schema) bool {
+						return proto.Equal(findTrigger(trigger.GetName(), scheme.GetTriggers().GetTriggers()), trigger)
+					})
+					if err != nil {
+						return err
+					}
+					data.SetId(trigger.GetName())
+					return nil
 				},
-				"graphik_constraint": {
-					Schema:         constraintSchema,
-					SchemaVersion:  0,
-					MigrateState:   nil,
-					StateUpgraders: nil,
-					Create: func(data *schema.ResourceData, i interface{}) error {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						client := i.(*graphik.Client)
-						scheme, err := client.GetSchema(ctx, &empty.Empty{})
-						if err != nil {
-							return err
-						}
-						values := scheme.GetConstraints().GetConstraints()
-						data.SetId(data.Get("name").(string))
-						var has = false
-						for i, a := range values {
-							if a.GetName() == data.Get("name") {
-								has = true
-								values[i] = &apipb.Constraint{
-									Name:              data.Get("name").(string),
-									Gtype:             data.Get("gtype").(string),
-									Expression:        data.Get("expression").(string),
-									TargetDocs:        data.Get("target_docs").(bool),
-									TargetConnections: data.Get("target_connections").(bool),
-								}
-							}
-						}
-
-						if !has {
-							values = append(values, &apipb.Constraint{
-								Name:              data.Get("name").(string),
-								Gtype:             data.Get("gtype").(string),
-								Expression:        data.Get("expression").(string),
-								TargetDocs:        data.Get("target_docs").(bool),
-								TargetConnections: data.Get("target_connections").(bool),
-							})
-						}
-						if err := client.SetConstraints(ctx, &apipb.Constraints{Constraints: values}); err != nil {
-							return err
-						}
+				Read: func(data *schema.ResourceData, i interface{}) error {
+					meta := i.(*providerMeta)
+					ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutRead))
+					defer cancel()
+					scheme, err := meta.getSchema(ctx)
+					if err != nil {
+						return err
+					}
+					trigger := findTrigger(data.Id(), scheme.GetTriggers().GetTriggers())
+					if trigger == nil {
+						// the trigger was removed outside of terraform
+						data.SetId("")
 						return nil
-					},
-					Read: func(data *schema.ResourceData, i interface{}) error {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						client := i.(*graphik.Client)
-						scheme, err := client.GetSchema(ctx, &empty.Empty{})
-						if err != nil {
-							return err
-						}
-						id := data.Id()
-						for _, a := range scheme.GetConstraints().GetConstraints() {
-							if a.GetName() == id {
-								if err := data.Set("name", a.GetName()); err != nil {
-									return err
-								}
-								if err := data.Set("gtype", a.GetGtype()); err != nil {
-									return err
-								}
-								if err := data.Set("expression", a.GetExpression()); err != nil {
-									return err
-								}
-								if err := data.Set("target_connections", a.GetTargetConnections()); err != nil {
-									return err
-								}
-								if err := data.Set("target_docs", a.GetTargetDocs()); err != nil {
-									return err
-								}
-							}
-						}
-						return nil
-					},
-					Update: func(data *schema.ResourceData, i interface{}) error {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						client := i.(*graphik.Client)
-						scheme, err := client.GetSchema(ctx, &empty.Empty{})
-						if err != nil {
-							return err
-						}
-						values := scheme.GetConstraints().GetConstraints()
-						data.SetId(data.Get("name").(string))
-						var has = false
-						for i, a := range values {
-							if a.GetName() == data.Get("name") {
-								has = true
-								values[i] = &apipb.Constraint{
-									Name:              data.Get("name").(string),
-									Gtype:             data.Get("gtype").(string),
-									Expression:        data.Get("expression").(string),
-									TargetDocs:        data.Get("target_docs").(bool),
-									TargetConnections: data.Get("target_connections").(bool),
-								}
-							}
-						}
-
-						if !has {
-							values = append(values, &apipb.Constraint{
-								Name:              data.Get("name").(string),
-								Gtype:             data.Get("gtype").(string),
-								Expression:        data.Get("expression").(string),
-								TargetDocs:        data.Get("target_docs").(bool),
-								TargetConnections: data.Get("target_connections").(bool),
-							})
-						}
-						if err := client.SetConstraints(ctx, &apipb.Constraints{Constraints: values}); err != nil {
-							return err
-						}
-						return nil
-					},
-					Delete: func(data *schema.ResourceData, i interface{}) error {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						client := i.(*graphik.Client)
-						scheme, err := client.GetSchema(ctx, &empty.Empty{})
-						if err != nil {
-							return err
-						}
-						values := scheme.GetConstraints().GetConstraints()
-						var index = -1
-						for i, a := range values {
-							if a.GetName() == data.Get("name") {
-								index = i
-								values[i] = &apipb.Constraint{
-									Name:              data.Get("name").(string),
-									Gtype:             data.Get("gtype").(string),
-									Expression:        data.Get("expression").(string),
-									TargetDocs:        data.Get("target_docs").(bool),
-									TargetConnections: data.Get("target_connections").(bool),
-								}
-							}
-						}
-						if index >= 0 {
-							removeConstraint(index, values)
-							if err := client.SetConstraints(ctx, &apipb.Constraints{Constraints: values}); err != nil {
-								return err
-							}
-						}
-						return nil
-					},
-					Exists: func(data *schema.ResourceData, i interface{}) (bool, error) {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						client := i.(*graphik.Client)
-						scheme, err := client.GetSchema(ctx, &empty.Empty{})
-						if err != nil {
-							return false, err
-						}
-						values := scheme.GetConstraints()
-						var has = false
-						for _, a := range values.GetConstraints() {
-							if a.GetName() == data.Id() {
-								has = true
-							}
-						}
-						return has, nil
-					},
-					CustomizeDiff: nil,
-					Importer: &schema.ResourceImporter{
-						State: schema.ImportStatePassthrough,
-					},
-					DeprecationMessage: "",
-					Timeouts:           nil,
-					Description:        "a graph primitive used to validate custom doc/connection constraints",
+					}
+					return setTriggerData(data, trigger)
 				},
-				"graphik_authorizer": {
-					Schema:         authorizerSchema,
-					SchemaVersion:  0,
-					MigrateState:   nil,
-					StateUpgraders: nil,
-					Create: func(data *schema.ResourceData, i interface{}) error {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						client := i.(*graphik.Client)
-						scheme, err := client.GetSchema(ctx, &empty.Empty{})
-						if err != nil {
-							return err
-						}
-						authorizers := scheme.GetAuthorizers()
-						data.SetId(data.Get("name").(string))
-						var has = false
-						for i, a := range authorizers.GetAuthorizers() {
-							if a.GetName() == data.Get("name") {
-								has = true
-								authorizers.Authorizers[i] = &apipb.Authorizer{
-									Name:            data.Get("name").(string),
-									Method:          data.Get("method").(string),
-									Expression:      data.Get("expression").(string),
-									TargetRequests:  data.Get("target_requests").(bool),
-									TargetResponses: data.Get("target_responses").(bool),
-								}
-							}
-						}
-
-						if !has {
-							authorizers.Authorizers = append(authorizers.Authorizers, &apipb.Authorizer{
-								Name:            data.Id(),
-								Method:          data.Get("method").(string),
-								Expression:      data.Get("expression").(string),
-								TargetRequests:  data.Get("target_requests").(bool),
-								TargetResponses: data.Get("target_responses").(bool),
-							})
-						}
-						if err := client.SetAuthorizers(ctx, authorizers); err != nil {
-							return err
-						}
-						return nil
-					},
-					Read: func(data *schema.ResourceData, i interface{}) error {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						client := i.(*graphik.Client)
-						scheme, err := client.GetSchema(ctx, &empty.Empty{})
-						if err != nil {
-							return err
-						}
-						id := data.Id()
-						for _, a := range scheme.GetAuthorizers().GetAuthorizers() {
-							if a.GetName() == id {
-								if err := data.Set("name", a.GetName()); err != nil {
-									return err
-								}
-								if err := data.Set("expression", a.GetExpression()); err != nil {
-									return err
-								}
-								if err := data.Set("method", a.GetMethod()); err != nil {
-									return err
-								}
-								if err := data.Set("target_requests", a.GetTargetRequests()); err != nil {
-									return err
-								}
-								if err := data.Set("target_responses", a.GetTargetResponses()); err != nil {
-									return err
-								}
-							}
-						}
-						return nil
-					},
-					Update: func(data *schema.ResourceData, i interface{}) error {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						client := i.(*graphik.Client)
-						scheme, err := client.GetSchema(ctx, &empty.Empty{})
-						if err != nil {
-							return err
-						}
-						authorizers := scheme.GetAuthorizers()
-						var has = false
-						for i, a := range authorizers.GetAuthorizers() {
-							if a.GetName() == data.Get("name") {
-								has = true
-								authorizers.Authorizers[i] = &apipb.Authorizer{
-									Name:            data.Get("name").(string),
-									Method:          data.Get("method").(string),
-									Expression:      data.Get("expression").(string),
-									TargetRequests:  data.Get("target_requests").(bool),
-									TargetResponses: data.Get("target_responses").(bool),
-								}
-							}
-						}
-						data.SetId(data.Get("name").(string))
-						if !has {
-							authorizers.Authorizers = append(authorizers.Authorizers, &apipb.Authorizer{
-								Name:            data.Id(),
-								Method:          data.Get("method").(string),
-								Expression:      data.Get("expression").(string),
-								TargetRequests:  data.Get("target_requests").(bool),
-								TargetResponses: data.Get("target_responses").(bool),
-							})
-						}
-						if err := client.SetAuthorizers(ctx, authorizers); err != nil {
-							return err
-						}
+				Update: func(data *schema.ResourceData, i interface{}) error {
+					meta := i.(*providerMeta)
+					ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutUpdate))
+					defer cancel()
+					trigger := triggerFromData(data)
+					err := meta.updateSchema(ctx, triggersKind, func(scheme *apipb.Schema) {
+						scheme.Triggers = &apipb.Triggers{Triggers: upsertTrigger(trigger, scheme.GetTriggers().GetTriggers())}
+					}, func(scheme *apipb.Schema) bool {
+						return proto.Equal(findTrigger(trigger.GetName(), scheme.GetTriggers().GetTriggers()), trigger)
+					})
+					if err != nil {
+						return err
+					}
+					data.SetId(trigger.GetName())
+					return nil
+				},
+				Delete: func(data *schema.ResourceData, i interface{}) error {
+					meta := i.(*providerMeta)
+					ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutDelete))
+					defer cancel()
+					return meta.updateSchema(ctx, triggersKind, func(scheme *apipb.Schema) {
+						scheme.Triggers = &apipb.Triggers{Triggers: removeTrigger(data.Id(), scheme.GetTriggers().GetTriggers())}
+					}, func(scheme *apipb.Schema) bool {
+						return findTrigger(data.Id(), scheme.GetTriggers().GetTriggers()) == nil
+					})
+				},
+				CustomizeDiff:      nil,
+				Importer:           importPrimitive(bulkTriggers),
+				DeprecationMessage: "",
+				Timeouts:           resourceTimeouts(),
+				Description:        "used to automatically mutate the attributes of documents/connections before they are commited to the database",
+			},
+			"graphik_constraint": {
+				Schema:         constraintSchema,
+				SchemaVersion:  0,
+				MigrateState:   nil,
+				StateUpgraders: nil,
+				Create: func(data *schema.ResourceData, i interface{}) error {
+					meta := i.(*providerMeta)
+					ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutCreate))
+					defer cancel()
+					constraint := constraintFromData(data)
+					err := meta.updateSchema(ctx, constraintsKind, func(scheme *apipb.Schema) {
+						scheme.Constraints = &apipb.Constraints{Constraints: upsertConstraint(constraint, scheme.GetConstraints().GetConstraints())}
+					}, func(scheme *apipb.Schema) bool {
+						return proto.Equal(findConstraint(constraint.GetName(), scheme.GetConstraints().GetConstraints()), constraint)
+					})
+					if err != nil {
+						return err
+					}
+					data.SetId(constraint.GetName())
+					return nil
+				},
+				Read: func(data *schema.ResourceData, i interface{}) error {
+					meta := i.(*providerMeta)
+					ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutRead))
+					defer cancel()
+					scheme, err := meta.getSchema(ctx)
+					if err != nil {
+						return err
+					}
+					constraint := findConstraint(data.Id(), scheme.GetConstraints().GetConstraints())
+					if constraint == nil {
+						// the constraint was removed outside of terraform
+						data.SetId("")
 						return nil
-					},
-					Delete: func(data *schema.ResourceData, i interface{}) error {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						client := i.(*graphik.Client)
-						scheme, err := client.GetSchema(ctx, &empty.Empty{})
-						if err != nil {
-							return err
-						}
-						authorizers := scheme.GetAuthorizers().GetAuthorizers()
-						var index = -1
-						for i, a := range authorizers {
-							if a.GetName() == data.Get("name") {
-								index = i
-								authorizers[i] = &apipb.Authorizer{
-									Name:            data.Get("name").(string),
-									Method:          data.Get("method").(string),
-									Expression:      data.Get("expression").(string),
-									TargetRequests:  data.Get("target_requests").(bool),
-									TargetResponses: data.Get("target_responses").(bool),
-								}
-							}
-						}
-						if index >= 0 {
-							removeAuthorizer(index, authorizers)
-							if err := client.SetAuthorizers(ctx, &apipb.Authorizers{Authorizers: authorizers}); err != nil {
-								return err
-							}
-						}
+					}
+					return setConstraintData(data, constraint)
+				},
+				Update: func(data *schema.ResourceData, i interface{}) error {
+					meta := i.(*providerMeta)
+					ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutUpdate))
+					defer cancel()
+					constraint := constraintFromData(data)
+					err := meta.updateSchema(ctx, constraintsKind, func(scheme *apipb.Schema) {
+						scheme.Constraints = &apipb.Constraints{Constraints: upsertConstraint(constraint, scheme.GetConstraints().GetConstraints())}
+					}, func(scheme *apipb.Schema) bool {
+						return proto.Equal(findConstraint(constraint.GetName(), scheme.GetConstraints().GetConstraints()), constraint)
+					})
+					if err != nil {
+						return err
+					}
+					data.SetId(constraint.GetName())
+					return nil
+				},
+				Delete: func(data *schema.ResourceData, i interface{}) error {
+					meta := i.(*providerMeta)
+					ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutDelete))
+					defer cancel()
+					return meta.updateSchema(ctx, constraintsKind, func(scheme *apipb.Schema) {
+						scheme.Constraints = &apipb.Constraints{Constraints: removeConstraint(data.Id(), scheme.GetConstraints().GetConstraints())}
+					}, func(scheme *apipb.Schema) bool {
+						return findConstraint(data.Id(), scheme.GetConstraints().GetConstraints()) == nil
+					})
+				},
+				CustomizeDiff:      nil,
+				Importer:           importPrimitive(bulkConstraints),
+				DeprecationMessage: "",
+				Timeouts:           resourceTimeouts(),
+				Description:        "a graph primitive used to validate custom doc/connection constraints",
+			},
+			"graphik_authorizer": {
+				Schema:         authorizerSchema,
+				SchemaVersion:  0,
+				MigrateState:   nil,
+				StateUpgraders: nil,
+				Create: func(data *schema.ResourceData, i interface{}) error {
+					meta := i.(*providerMeta)
+					ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutCreate))
+					defer cancel()
+					authorizer := authorizerFromData(data)
+					err := meta.updateSchema(ctx, authorizersKind, func(scheme *apipb.Schema) {
+						scheme.Authorizers = &apipb.Authorizers{Authorizers: upsertAuthorizer(authorizer, scheme.GetAuthorizers().GetAuthorizers())}
+					}, func(scheme *apipb.Schema) bool {
+						return proto.Equal(findAuthorizer(authorizer.GetName(), scheme.GetAuthorizers().GetAuthorizers()), authorizer)
+					})
+					if err != nil {
+						return err
+					}
+					data.SetId(authorizer.GetName())
+					return nil
+				},
+				Read: func(data *schema.ResourceData, i interface{}) error {
+					meta := i.(*providerMeta)
+					ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutRead))
+					defer cancel()
+					scheme, err := meta.getSchema(ctx)
+					if err != nil {
+						return err
+					}
+					authorizer := findAuthorizer(data.Id(), scheme.GetAuthorizers().GetAuthorizers())
+					if authorizer == nil {
+						// the authorizer was removed outside of terraform
+						data.SetId("")
 						return nil
-					},
-					Exists: func(data *schema.ResourceData, i interface{}) (bool, error) {
-						ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-						defer cancel()
-						client := i.(*graphik.Client)
-						scheme, err := client.GetSchema(ctx, &empty.Empty{})
-						if err != nil {
-							return false, err
-						}
-						authorizers := scheme.GetAuthorizers()
-						var has = false
-						for _, a := range authorizers.GetAuthorizers() {
-							if a.GetName() == data.Id() {
-								has = true
-							}
-						}
-						return has, nil
-					},
-					Importer: &schema.ResourceImporter{
-						State: schema.ImportStatePassthrough,
-					},
-					Description: "a graph primitive used for authorizing inbound requests and/or responses(see AuthTarget)",
+					}
+					return setAuthorizerData(data, authorizer)
+				},
+				Update: func(data *schema.ResourceData, i interface{}) error {
+					meta := i.(*providerMeta)
+					ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutUpdate))
+					defer cancel()
+					authorizer := authorizerFromData(data)
+					err := meta.updateSchema(ctx, authorizersKind, func(scheme *apipb.Schema) {
+						scheme.Authorizers = &apipb.Authorizers{Authorizers: upsertAuthorizer(authorizer, scheme.GetAuthorizers().GetAuthorizers())}
+					}, func(scheme *apipb.Schema) bool {
+						return proto.Equal(findAuthorizer(authorizer.GetName(), scheme.GetAuthorizers().GetAuthorizers()), authorizer)
+					})
+					if err != nil {
+						return err
+					}
+					data.SetId(authorizer.GetName())
+					return nil
+				},
+				Delete: func(data *schema.ResourceData, i interface{}) error {
+					meta := i.(*providerMeta)
+					ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutDelete))
+					defer cancel()
+					return meta.updateSchema(ctx, authorizersKind, func(scheme *apipb.Schema) {
+						scheme.Authorizers = &apipb.Authorizers{Authorizers: removeAuthorizer(data.Id(), scheme.GetAuthorizers().GetAuthorizers())}
+					}, func(scheme *apipb.Schema) bool {
+						return findAuthorizer(data.Id(), scheme.GetAuthorizers().GetAuthorizers()) == nil
+					})
 				},
+				Importer:    importPrimitive(bulkAuthorizers),
+				Timeouts:    resourceTimeouts(),
+				Description: "a graph primitive used for authorizing inbound requests and/or responses(see AuthTarget)",
 			},
-			ConfigureFunc: func(data *schema.ResourceData) (interface{}, error) {
-				ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-				defer cancel()
-				host := data.Get("host").(string)
-				metadataUri := data.Get("open_id").(string)
-				metadata := map[string]interface{}{}
-				resp, err := http.Get(metadataUri)
-				if err != nil {
-					return nil, errors.Wrap(err, "failed to get oidc metadata")
-				}
-				defer resp.Body.Close()
-				if err := json.NewDecoder(resp.Body).Decode(&metadata); err != nil {
-					return nil, errors.Wrap(err, "failed to get oidc metadata")
+			"graphik_indexes":     resourceBulk(bulkIndexes, "authoritatively manages every index: indexes that aren't declared are removed. don't use alongside graphik_index"),
+			"graphik_triggers":    resourceBulk(bulkTriggers, "authoritatively manages every trigger: triggers that aren't declared are removed. don't use alongside graphik_trigger"),
+			"graphik_constraints": resourceBulk(bulkConstraints, "authoritatively manages every constraint: constraints that aren't declared are removed. don't use alongside graphik_constraint"),
+			"graphik_authorizers": resourceBulk(bulkAuthorizers, "authoritatively manages every authorizer: authorizers that aren't declared are removed. don't use alongside graphik_authorizer"),
+			"graphik_doc":         resourceDoc(),
+			"graphik_connection":  resourceConnection(),
+			"graphik_docs":        resourceDocs(),
+		},
+		ConfigureFunc: func(data *schema.ResourceData) (interface{}, error) {
+			ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+			defer cancel()
+			profile := data.Get("profile").(string)
+			if err := checkProfile(profile); err != nil {
+				return nil, err
+			}
+			host, _ := providerSetting(data, profile, "host", "host")
+			if host == "" {
+				return nil, missingSetting(profile, "host", "host")
+			}
+			accessToken, _ := providerSetting(data, profile, "access_token", "auth.access_token")
+			openID, openIDSource := providerSetting(data, profile, "open_id", "auth.open_id")
+			if openID != "" {
+				if u, err := url.Parse(openID); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+					return nil, errors.Errorf("open_id from %s must be an http(s) url, got: %q", openIDSource, openID)
 				}
-				client, err := graphik.NewClient(ctx, host,
-					graphik.WithTokenSource(oauth2.StaticTokenSource(&oauth2.Token{
-						AccessToken: data.Get("access_token").(string),
-					})),
-					graphik.WithRetry(2),
-				)
+			}
+			switch grant := data.Get("grant_type").(string); {
+			case grant == "" && accessToken == "":
+				return nil, errors.Wrap(missingSetting(profile, "access_token", "auth.access_token"), "grant_type is not set")
+			case grant != "" && openID == "":
+				return nil, errors.Wrapf(missingSetting(profile, "open_id", "auth.open_id"), "grant_type %s needs the token_endpoint of the open id connect metadata", grant)
+			}
+			httpClient := newHTTPClient()
+			var metadata *oidcMetadata
+			if openID != "" {
+				var err error
+				metadata, err = fetchMetadata(ctx, httpClient, openID, oidcCacheDir())
 				if err != nil {
-					return nil, errors.Wrap(err, "failed to create graphik client")
+					return nil, err
 				}
-				return client, nil
-			},
+			}
+			if data.Get("grant_type").(string) == "" {
+				if err := verifyAccessToken(ctx, httpClient, accessToken, metadata, oidcCacheDir()); err != nil {
+					return nil, err
+				}
+			}
+			// the token source outlives configuration so it can't use the configuration timeout
+			tokens, err := tokenSource(context.WithValue(context.Background(), oauth2.HTTPClient, httpClient), data, accessToken, metadata)
+			if err != nil {
+				return nil, err
+			}
+			opts := []graphik.Opt{
+				graphik.WithTokenSource(tokens),
+				// calls are retried by the provider's retry policy instead of the client
+				graphik.WithRetry(0),
+			}
+			creds, err := transportCredentials(data)
+			if err != nil {
+				return nil, err
+			}
+			if creds != nil {
+				opts = append(opts, graphik.WithTransportCreds(creds))
+			}
+			client, err := graphik.NewClient(ctx, host, opts...)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to create graphik client")
+			}
+			if err := checkConnection(ctx, client, host); err != nil {
+				return nil, err
+			}
+			meta := newProviderMeta(client)
+			meta.strict = data.Get("strict_concurrency").(bool)
+			meta.requestTimeout, _ = time.ParseDuration(data.Get("request_timeout").(string))
+			if err := configureRetries(meta, data); err != nil {
+				return nil, err
+			}
+			return meta, nil
+		},
+	}
+}
+
+// indexFromData builds the index declared by graphik_index resource data
+func indexFromData(data *schema.ResourceData) *apipb.Index {
+	return &apipb.Index{
+		Name:              data.Get("name").(string),
+		Gtype:             data.Get("gtype").(string),
+		Expression:        data.Get("expression").(string),
+		TargetDocs:        data.Get("target_docs").(bool),
+		TargetConnections: data.Get("target_connections").(bool),
+	}
+}
+
+// setIndexData copies the index into graphik_index resource data
+func setIndexData(data *schema.ResourceData, value *apipb.Index) error {
+	if err := data.Set("name", value.GetName()); err != nil {
+		return err
+	}
+	if err := data.Set("gtype", value.GetGtype()); err != nil {
+		return err
+	}
+	if err := data.Set("expression", value.GetExpression()); err != nil {
+		return err
+	}
+	if err := data.Set("target_connections", value.GetTargetConnections()); err != nil {
+		return err
+	}
+	if err := data.Set("target_docs", value.GetTargetDocs()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// findIndex returns the index with the given name or nil if it doesn't exist
+func findIndex(name string, values []*apipb.Index) *apipb.Index {
+	for _, v := range values {
+		if v.GetName() == name {
+			return v
+		}
+	}
+	return nil
+}
+
+// upsertIndex replaces the index with the same name as value or appends it if it doesn't exist
+func upsertIndex(value *apipb.Index, values []*apipb.Index) []*apipb.Index {
+	return append(removeIndex(value.GetName(), values), value)
+}
+
+// triggerFromData builds the trigger declared by graphik_trigger resource data
+func triggerFromData(data *schema.ResourceData) *apipb.Trigger {
+	return &apipb.Trigger{
+		Name:              data.Get("name").(string),
+		Gtype:             data.Get("gtype").(string),
+		Trigger:           triggerArrow(data.Get("expression").(string), data.Get("trigger").(string)),
+		TargetDocs:        data.Get("target_docs").(bool),
+		TargetConnections: data.Get("target_connections").(bool),
+	}
+}
+
+// setTriggerData copies the trigger into graphik_trigger resource data
+func setTriggerData(data *schema.ResourceData, value *apipb.Trigger) error {
+	expression, trigger := splitTriggerArrow(value.GetTrigger())
+	if err := data.Set("name", value.GetName()); err != nil {
+		return err
+	}
+	if err := data.Set("gtype", value.GetGtype()); err != nil {
+		return err
+	}
+	if err := data.Set("expression", expression); err != nil {
+		return err
+	}
+	if err := data.Set("trigger", trigger); err != nil {
+		return err
+	}
+	if err := data.Set("target_connections", value.GetTargetConnections()); err != nil {
+		return err
+	}
+	if err := data.Set("target_docs", value.GetTargetDocs()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// findTrigger returns the trigger with the given name or nil if it doesn't exist
+func findTrigger(name string, values []*apipb.Trigger) *apipb.Trigger {
+	for _, v := range values {
+		if v.GetName() == name {
+			return v
+		}
+	}
+	return nil
+}
+
+// upsertTrigger replaces the trigger with the same name as value or appends it if it doesn't exist
+func upsertTrigger(value *apipb.Trigger, values []*apipb.Trigger) []*apipb.Trigger {
+	return append(removeTrigger(value.GetName(), values), value)
+}
+
+// constraintFromData builds the constraint declared by graphik_constraint resource data
+func constraintFromData(data *schema.ResourceData) *apipb.Constraint {
+	return &apipb.Constraint{
+		Name:              data.Get("name").(string),
+		Gtype:             data.Get("gtype").(string),
+		Expression:        data.Get("expression").(string),
+		TargetDocs:        data.Get("target_docs").(bool),
+		TargetConnections: data.Get("target_connections").(bool),
+	}
+}
+
+// setConstraintData copies the constraint into graphik_constraint resource data
+func setConstraintData(data *schema.ResourceData, value *apipb.Constraint) error {
+	if err := data.Set("name", value.GetName()); err != nil {
+		return err
+	}
+	if err := data.Set("gtype", value.GetGtype()); err != nil {
+		return err
+	}
+	if err := data.Set("expression", value.GetExpression()); err != nil {
+		return err
+	}
+	if err := data.Set("target_connections", value.GetTargetConnections()); err != nil {
+		return err
+	}
+	if err := data.Set("target_docs", value.GetTargetDocs()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// findConstraint returns the constraint with the given name or nil if it doesn't exist
+func findConstraint(name string, values []*apipb.Constraint) *apipb.Constraint {
+	for _, v := range values {
+		if v.GetName() == name {
+			return v
+		}
+	}
+	return nil
+}
+
+// upsertConstraint replaces the constraint with the same name as value or appends it if it doesn't exist
+func upsertConstraint(value *apipb.Constraint, values []*apipb.Constraint) []*apipb.Constraint {
+	return append(removeConstraint(value.GetName(), values), value)
+}
+
+// authorizerFromData builds the authorizer declared by graphik_authorizer resource data
+func authorizerFromData(data *schema.ResourceData) *apipb.Authorizer {
+	return &apipb.Authorizer{
+		Name:            data.Get("name").(string),
+		Method:          data.Get("method").(string),
+		Expression:      data.Get("expression").(string),
+		TargetRequests:  data.Get("target_requests").(bool),
+		TargetResponses: data.Get("target_responses").(bool),
+	}
+}
+
+// setAuthorizerData copies the authorizer into graphik_authorizer resource data
+func setAuthorizerData(data *schema.ResourceData, value *apipb.Authorizer) error {
+	if err := data.Set("name", value.GetName()); err != nil {
+		return err
+	}
+	if err := data.Set("expression", value.GetExpression()); err != nil {
+		return err
+	}
+	if err := data.Set("method", value.GetMethod()); err != nil {
+		return err
+	}
+	if err := data.Set("target_requests", value.GetTargetRequests()); err != nil {
+		return err
+	}
+	if err := data.Set("target_responses", value.GetTargetResponses()); err != nil {
+		return err
+	}
+	return nil
+}
+
+// findAuthorizer returns the authorizer with the given name or nil if it doesn't exist
+func findAuthorizer(name string, values []*apipb.Authorizer) *apipb.Authorizer {
+	for _, v := range values {
+		if v.GetName() == name {
+			return v
 		}
-	}})
+	}
+	return nil
 }
 
-func removeAuthorizer(i int, values []*apipb.Authorizer) {
-	values[i] = values[len(values)-1]
-	values[len(values)-1] = nil
-	values = values[:len(values)-1]
+// upsertAuthorizer replaces the authorizer with the same name as value or appends it if it doesn't exist
+func upsertAuthorizer(value *apipb.Authorizer, values []*apipb.Authorizer) []*apipb.Authorizer {
+	return append(removeAuthorizer(value.GetName(), values), value)
 }
 
-func removeIndex(i int, values []*apipb.Index) {
-	values[i] = values[len(values)-1]
-	values[len(values)-1] = nil
-	values = values[:len(values)-1]
+// removeAuthorizer returns the authorizers that don't match the given name
+func removeAuthorizer(name string, values []*apipb.Authorizer) []*apipb.Authorizer {
+	var remaining []*apipb.Authorizer
+	for _, v := range values {
+		if v != nil && v.GetName() != name {
+			remaining = append(remaining, v)
+		}
+	}
+	return remaining
 }
 
-func removeConstraint(i int, values []*apipb.Constraint) {
-	values[i] = values[len(values)-1]
-	values[len(values)-1] = nil
-	values = values[:len(values)-1]
+// removeIndex returns the indexes that don't match the given name
+func removeIndex(name string, values []*apipb.Index) []*apipb.Index {
+	var remaining []*apipb.Index
+	for _, v := range values {
+		if v != nil && v.GetName() != name {
+			remaining = append(remaining, v)
+		}
+	}
+	return remaining
 }
 
-func removeTrigger(i int, values []*apipb.Trigger) {
-	values[i] = values[len(values)-1]
-	values[len(values)-1] = nil
-	values = values[:len(values)-1]
+// removeConstraint returns the constraints that don't match the given name
+func removeConstraint(name string, values []*apipb.Constraint) []*apipb.Constraint {
+	var remaining []*apipb.Constraint
+	for _, v := range values {
+		if v != nil && v.GetName() != name {
+			remaining = append(remaining, v)
+		}
+	}
+	return remaining
+}
+
+// removeTrigger returns the triggers that don't match the given name
+func removeTrigger(name string, values []*apipb.Trigger) []*apipb.Trigger {
+	var remaining []*apipb.Trigger
+	for _, v := range values {
+		if v != nil && v.GetName() != name {
+			remaining = append(remaining, v)
+		}
+	}
+	return remaining
+}
+
+// validateDuration validates that a string attribute is a valid duration(ex: 30s, 2m)
+func validateDuration(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{errors.Errorf("expected type of %s to be string", k)}
+	}
+	if _, err := time.ParseDuration(v); err != nil {
+		return nil, []error{errors.Wrapf(err, "expected %s to be a duration(ex: 30s, 2m)", k)}
+	}
+	return nil, nil
+}
+
+// triggerArrow joins a trigger's gating expression and its mutation into the arrow syntax expected by graphikDB
+// ref: https://github.com/graphikDB/trigger
+func triggerArrow(expression, trigger string) string {
+	return fmt.Sprintf("%s => %s", expression, trigger)
+}
+
+// splitTriggerArrow splits a graphikDB arrow trigger into its gating expression and its mutation
+func splitTriggerArrow(arrow string) (string, string) {
+	i := triggerArrowIndex(arrow)
+	if i < 0 {
+		return "true", strings.TrimSpace(arrow)
+	}
+	return strings.TrimSpace(arrow[:i]), strings.TrimSpace(arrow[i+len("=>"):])
+}
+
+// triggerArrowIndex returns the byte offset of the first '=>' of an arrow trigger that isn't inside a string literal, or -1
+func triggerArrowIndex(arrow string) int {
+	for i := 0; i < len(arrow); i++ {
+		switch c := arrow[i]; {
+		case c == '\'' || c == '"':
+			raw := i > 0 && (arrow[i-1] == 'r' || arrow[i-1] == 'R')
+			quote := string(c)
+			if strings.HasPrefix(arrow[i:], strings.Repeat(quote, 3)) {
+				quote = strings.Repeat(quote, 3)
+			}
+			for i += len(quote); i < len(arrow) && !strings.HasPrefix(arrow[i:], quote); i++ {
+				if arrow[i] == '\\' && !raw {
+					i++
+				}
+			}
+			i += len(quote) - 1
+		case strings.HasPrefix(arrow[i:], "=>"):
+			return i
+		}
+	}
+	return -1
+}
+
+// triggerResourceV0 is the graphik_trigger schema prior to the addition of the expression attribute
+func triggerResourceV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"gtype": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"trigger": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"target_docs": {
+				Type:     schema.TypeBool,
+				Required: true,
+			},
+			"target_connections": {
+				Type:     schema.TypeBool,
+				Required: true,
+			},
+		},
+	}
+}
+
+// upgradeTriggerStateV0 populates the expression attribute of graphik_trigger state written before it existed
+func upgradeTriggerStateV0(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+	trigger, _ := rawState["trigger"].(string)
+	expression, trigger := splitTriggerArrow(trigger)
+	rawState["expression"] = expression
+	rawState["trigger"] = trigger
+	return rawState, nil
 }
 
 func initConfig() {
@@ -898,3 +963,109 @@ func initConfig() {
 	viper.AutomaticEnv() // read in environment variables that match
 	viper.ReadInConfig()
 }
+
+// checkProfile returns an error if the named profile isn't declared in the graphikctl config
+func checkProfile(profile string) error {
+	if profile != "" && !viper.IsSet("profiles."+profile) {
+		return errors.Errorf("profile %s not found in graphikctl config %s", profile, viper.ConfigFileUsed())
+	}
+	return nil
+}
+
+// configValue returns the value of key in the named profile of the graphikctl config.
+// The top level of the config is used when profile is empty.
+func configValue(profile, key string) string {
+	if profile == "" {
+		return viper.GetString(key)
+	}
+	return viper.GetString("profiles." + profile + "." + key)
+}
+
+// providerSetting returns the provider argument & a description of where it was resolved from.
+// Unset arguments fall back to the GRAPHIK_<ARGUMENT> environment variable & then to the given key of the graphikctl config.
+func providerSetting(data *schema.ResourceData, profile, argument, configKey string) (string, string) {
+	if v := data.Get(argument).(string); v != "" {
+		return v, "the " + argument + " provider argument"
+	}
+	env := "GRAPHIK_" + strings.ToUpper(argument)
+	if v := os.Getenv(env); v != "" {
+		return v, "the " + env + " environment variable"
+	}
+	if v := configValue(profile, configKey); v != "" {
+		return v, configSource(profile, configKey)
+	}
+	return "", ""
+}
+
+// configSource describes the given key of the graphikctl config
+func configSource(profile, configKey string) string {
+	if profile != "" {
+		configKey = "profiles." + profile + "." + configKey
+	}
+	if file := viper.ConfigFileUsed(); file != "" {
+		return fmt.Sprintf("%s in the graphikctl config %s", configKey, file)
+	}
+	return fmt.Sprintf("%s in the graphikctl config (no config file was found)", configKey)
+}
+
+// missingSetting returns an error naming every source a provider argument can be resolved from
+func missingSetting(profile, argument, configKey string) error {
+	return errors.Errorf("%s is not set: set the %s provider argument, the GRAPHIK_%s environment variable or %s",
+		argument, argument, strings.ToUpper(argument), configSource(profile, configKey))
+}
+
+// checkConnection pings graphik & fetches the authenticated identity so that unreachable hosts & rejected credentials
+// fail provider configuration with a readable error instead of failing the first resource operation
+func checkConnection(ctx context.Context, client graphikClient, host string) error {
+	if _, err := client.Ping(ctx, &empty.Empty{}); err != nil {
+		return describeConnectionError(host, "ping", err)
+	}
+	if _, err := client.Me(ctx, &empty.Empty{}); err != nil {
+		return describeConnectionError(host, "get the authenticated identity", err)
+	}
+	return nil
+}
+
+// describeConnectionError explains a failed call made while configuring the provider
+func describeConnectionError(host, action string, err error) error {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return errors.Errorf("graphik at %s is unreachable (failed to %s): %s", host, action, status.Convert(err).Message())
+	case codes.Unauthenticated:
+		return errors.Errorf("graphik at %s rejected the access token (failed to %s): %s", host, action, status.Convert(err).Message())
+	case codes.PermissionDenied:
+		return errors.Errorf("the authenticated identity isn't authorized by graphik at %s (failed to %s): %s", host, action, status.Convert(err).Message())
+	default:
+		return errors.Wrapf(err, "failed to %s graphik at %s", action, host)
+	}
+}
+
+// grpcCodeNames returns the name of every gRPC status code
+func grpcCodeNames() []string {
+	var names []string
+	for code := codes.OK; code <= codes.Unauthenticated; code++ {
+		names = append(names, code.String())
+	}
+	return names
+}
+
+// configureRetries applies the retry policy provider arguments to the provider
+func configureRetries(meta *providerMeta, data *schema.ResourceData) error {
+	meta.maxRetries = data.Get("max_retries").(int)
+	meta.retryBackoffMin, _ = time.ParseDuration(data.Get("retry_backoff_min").(string))
+	meta.retryBackoffMax, _ = time.ParseDuration(data.Get("retry_backoff_max").(string))
+	if meta.retryBackoffMin > meta.retryBackoffMax {
+		return errors.Errorf("retry_backoff_min (%s) must not exceed retry_backoff_max (%s)", meta.retryBackoffMin, meta.retryBackoffMax)
+	}
+	if names := data.Get("retryable_codes").([]interface{}); len(names) > 0 {
+		meta.retryableCodes = map[codes.Code]bool{}
+		for _, name := range names {
+			for code := codes.OK; code <= codes.Unauthenticated; code++ {
+				if code.String() == name.(string) {
+					meta.retryableCodes[code] = true
+				}
+			}
+		}
+	}
+	return nil
+}
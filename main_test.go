@@ -0,0 +1,446 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	apipb "github.com/graphikDB/graphik/gen/grpc/go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// strictProviderMeta returns provider meta with strict_concurrency enabled
+func strictProviderMeta(client *fakeClient) *providerMeta {
+	meta := newProviderMeta(client)
+	meta.strict = true
+	return meta
+}
+
+var testResourceTypes = []string{
+	"graphik_index",
+	"graphik_trigger",
+	"graphik_constraint",
+	"graphik_authorizer",
+}
+
+func testResourceData(t *testing.T, resourceType, name string) (*schema.Resource, *schema.ResourceData) {
+	res := Provider().(*schema.Provider).ResourcesMap[resourceType]
+	raw := map[string]interface{}{
+		"name":       name,
+		"gtype":      "task",
+		"expression": "true",
+		"trigger":    "{'updated_at': now()}",
+		"method":     "/api.DatabaseService/GetSchema",
+	}
+	for k := range raw {
+		if _, ok := res.Schema[k]; !ok {
+			delete(raw, k)
+		}
+	}
+	data := schema.TestResourceDataRaw(t, res.Schema, raw)
+	data.SetId(name)
+	return res, data
+}
+
+func TestDelete(t *testing.T) {
+	for _, resourceType := range testResourceTypes {
+		t.Run(resourceType, func(t *testing.T) {
+			client := newFakeClient()
+			client.seed()
+			res, data := testResourceData(t, resourceType, "a")
+			if err := res.Delete(data, newProviderMeta(client)); err != nil {
+				t.Fatal(err)
+			}
+			names := client.names(resourceType)
+			if len(names) != 2 {
+				t.Fatalf("expected 2 remaining, got: %v", names)
+			}
+			for _, name := range names {
+				if name == "a" {
+					t.Fatalf("expected a to be deleted, got: %v", names)
+				}
+			}
+			// deleting an already deleted primitive is a no-op
+			if err := res.Delete(data, newProviderMeta(client)); err != nil {
+				t.Fatal(err)
+			}
+			if names := client.names(resourceType); len(names) != 2 {
+				t.Fatalf("expected 2 remaining, got: %v", names)
+			}
+		})
+	}
+}
+
+func TestDeleteNotApplied(t *testing.T) {
+	for _, resourceType := range testResourceTypes {
+		t.Run(resourceType, func(t *testing.T) {
+			client := newFakeClient()
+			client.seed()
+			client.dropSets = -1
+			res, data := testResourceData(t, resourceType, "b")
+			if err := res.Delete(data, strictProviderMeta(client)); err == nil {
+				t.Fatal("expected delete to fail when the server still lists the primitive")
+			}
+		})
+	}
+}
+
+func TestRemoveIndex(t *testing.T) {
+	values := []*apipb.Index{{Name: "a"}, {Name: "b"}, nil, {Name: "b"}, {Name: "c"}}
+	remaining := removeIndex("b", values)
+	if len(remaining) != 2 || remaining[0].GetName() != "a" || remaining[1].GetName() != "c" {
+		t.Fatalf("unexpected remaining indexes: %v", remaining)
+	}
+}
+
+func TestConcurrentCreate(t *testing.T) {
+	for _, resourceType := range testResourceTypes {
+		t.Run(resourceType, func(t *testing.T) {
+			client := newFakeClient()
+			meta := newProviderMeta(client)
+			var wg sync.WaitGroup
+			errs := make(chan error, 20)
+			for i := 0; i < 20; i++ {
+				res, data := testResourceData(t, resourceType, fmt.Sprintf("concurrent_%v", i))
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					errs <- res.Create(data, meta)
+				}()
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if names := client.names(resourceType); len(names) != 20 {
+				t.Fatalf("expected 20 %s, got: %v", resourceType, names)
+			}
+		})
+	}
+}
+
+func TestClobberedCreate(t *testing.T) {
+	for _, resourceType := range testResourceTypes {
+		t.Run(resourceType, func(t *testing.T) {
+			client := newFakeClient()
+			client.dropSets = 1
+			res, data := testResourceData(t, resourceType, "clobbered")
+			if err := res.Create(data, newProviderMeta(client)); err != nil {
+				t.Fatal(err)
+			}
+			if names := client.names(resourceType); len(names) != 1 {
+				t.Fatalf("expected clobbered create to be retried, got: %v", names)
+			}
+		})
+		t.Run(resourceType+"_strict", func(t *testing.T) {
+			client := newFakeClient()
+			client.dropSets = 1
+			res, data := testResourceData(t, resourceType, "clobbered")
+			if err := res.Create(data, strictProviderMeta(client)); err == nil {
+				t.Fatal("expected clobbered create to fail in strict mode")
+			}
+		})
+	}
+}
+
+const testIndexConfig = testProviderConfig + `
+resource "graphik_index" "low_priority" {
+  name               = "low_priority"
+  gtype              = "task"
+  expression         = "this.attributes.priority == 'low'"
+  target_docs        = true
+  target_connections = false
+}
+`
+
+const testTriggerConfig = testProviderConfig + `
+resource "graphik_trigger" "created_at" {
+  name               = "created_at"
+  gtype              = "*"
+  expression         = "!has(this.attributes.created_at)"
+  trigger            = "{ 'created_at': now() }"
+  target_docs        = true
+  target_connections = true
+}
+`
+
+func TestAccIndex_removedOutOfBand(t *testing.T) {
+	client := newFakeClient()
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testProviders(client),
+		Steps: []resource.TestStep{
+			{
+				Config: testIndexConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("graphik_index.low_priority", "id", "low_priority"),
+					resource.TestCheckResourceAttr("graphik_index.low_priority", "expression", "this.attributes.priority == 'low'"),
+				),
+			},
+			{
+				PreConfig: func() {
+					client.mu.Lock()
+					defer client.mu.Unlock()
+					client.schema.Indexes.Indexes = nil
+				},
+				Config:             testIndexConfig,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testIndexConfig,
+				Check: func(state *terraform.State) error {
+					if names := client.names("graphik_index"); len(names) != 1 {
+						return errors.Errorf("expected index to be recreated, got: %v", names)
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestAccIndex_editedOutOfBand(t *testing.T) {
+	client := newFakeClient()
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testProviders(client),
+		Steps: []resource.TestStep{
+			{
+				Config: testIndexConfig,
+			},
+			{
+				PreConfig: func() {
+					client.mu.Lock()
+					defer client.mu.Unlock()
+					client.schema.Indexes.Indexes[0].TargetConnections = true
+				},
+				Config:             testIndexConfig,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: testIndexConfig,
+				Check:  resource.TestCheckResourceAttr("graphik_index.low_priority", "target_connections", "false"),
+			},
+		},
+	})
+}
+
+func TestAccTrigger_editedOutOfBand(t *testing.T) {
+	client := newFakeClient()
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testProviders(client),
+		Steps: []resource.TestStep{
+			{
+				Config: testTriggerConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("graphik_trigger.created_at", "expression", "!has(this.attributes.created_at)"),
+					resource.TestCheckResourceAttr("graphik_trigger.created_at", "trigger", "{ 'created_at': now() }"),
+				),
+			},
+			{
+				PreConfig: func() {
+					client.mu.Lock()
+					defer client.mu.Unlock()
+					client.schema.Triggers.Triggers[0].Trigger = triggerArrow("true", "{ 'created_at': now() }")
+				},
+				Config:             testTriggerConfig,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				PreConfig: func() {
+					client.mu.Lock()
+					defer client.mu.Unlock()
+					client.schema.Triggers.Triggers = nil
+				},
+				Config:             testTriggerConfig,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func TestSplitTriggerArrow(t *testing.T) {
+	for _, tc := range []struct {
+		arrow      string
+		expression string
+		trigger    string
+	}{
+		{arrow: "{'updated_at': now()}", expression: "true", trigger: "{'updated_at': now()}"},
+		{arrow: "this.gtype == 'task' => {'updated_at': now()}", expression: "this.gtype == 'task'", trigger: "{'updated_at': now()}"},
+		{arrow: "this.attributes.title.contains('=>') => {'arrow': true}", expression: "this.attributes.title.contains('=>')", trigger: "{'arrow': true}"},
+		{arrow: `this.attributes.note == "a \" => b" => {'escaped': true}`, expression: `this.attributes.note == "a \" => b"`, trigger: "{'escaped': true}"},
+		{arrow: "this.attributes.note == '''it's => here''' => {'triple': true}", expression: "this.attributes.note == '''it's => here'''", trigger: "{'triple': true}"},
+		{arrow: `this.attributes.path == r'C:\' => {'raw': true}`, expression: `this.attributes.path == r'C:\'`, trigger: "{'raw': true}"},
+		{arrow: "true => {'label': '=>'}", expression: "true", trigger: "{'label': '=>'}"},
+	} {
+		expression, trigger := splitTriggerArrow(tc.arrow)
+		if expression != tc.expression || trigger != tc.trigger {
+			t.Errorf("%q: expected %q & %q, got: %q & %q", tc.arrow, tc.expression, tc.trigger, expression, trigger)
+		}
+		// the arrow written back to graphik splits into the same expression & trigger, so there's no diff on the next plan
+		if expression, trigger := splitTriggerArrow(triggerArrow(expression, trigger)); expression != tc.expression || trigger != tc.trigger {
+			t.Errorf("%q: round trip returned %q & %q", tc.arrow, expression, trigger)
+		}
+	}
+}
+
+func TestUpgradeTriggerStateV0(t *testing.T) {
+	for _, tc := range []struct {
+		trigger    string
+		expression string
+		upgraded   string
+	}{
+		{trigger: "{'updated_at': now()}", expression: "true", upgraded: "{'updated_at': now()}"},
+		{trigger: "this.attributes.done => {'done_at': now()}", expression: "this.attributes.done", upgraded: "{'done_at': now()}"},
+		{trigger: "this.attributes.title != '=>' => {'titled': true}", expression: "this.attributes.title != '=>'", upgraded: "{'titled': true}"},
+	} {
+		state, err := upgradeTriggerStateV0(map[string]interface{}{
+			"id":          "updated_at",
+			"name":        "updated_at",
+			"gtype":       "task",
+			"trigger":     tc.trigger,
+			"target_docs": true,
+		}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := map[string]interface{}{
+			"id":          "updated_at",
+			"name":        "updated_at",
+			"gtype":       "task",
+			"expression":  tc.expression,
+			"trigger":     tc.upgraded,
+			"target_docs": true,
+		}
+		if !reflect.DeepEqual(state, expected) {
+			t.Errorf("%q: unexpected upgraded state: %v", tc.trigger, state)
+		}
+	}
+}
+
+func TestReadRemoved(t *testing.T) {
+	for _, resourceType := range testResourceTypes {
+		t.Run(resourceType, func(t *testing.T) {
+			client := newFakeClient()
+			client.seed()
+			res, data := testResourceData(t, resourceType, "d")
+			if err := res.Read(data, newProviderMeta(client)); err != nil {
+				t.Fatal(err)
+			}
+			if data.Id() != "" {
+				t.Fatalf("expected id to be cleared, got: %s", data.Id())
+			}
+		})
+	}
+}
+
+func TestConfigProfiles(t *testing.T) {
+	config := filepath.Join(t.TempDir(), ".graphikctl.yaml")
+	err := ioutil.WriteFile(config, []byte(`
+host: localhost:7820
+auth:
+  access_token: dev-token
+profiles:
+  prod:
+    host: graphik.prod:7820
+    auth:
+      access_token: prod-token
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer viper.Reset()
+	viper.SetConfigFile(config)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	providerSchema := Provider().(*schema.Provider).Schema
+	data := schema.TestResourceDataRaw(t, providerSchema, map[string]interface{}{"access_token": "explicit"})
+	for _, tc := range []struct {
+		profile, argument, configKey, expected string
+	}{
+		{argument: "host", configKey: "host", expected: "localhost:7820"},
+		{profile: "prod", argument: "host", configKey: "host", expected: "graphik.prod:7820"},
+		{profile: "prod", argument: "access_token", configKey: "auth.access_token", expected: "explicit"},
+		{profile: "prod", argument: "open_id", configKey: "auth.open_id", expected: ""},
+	} {
+		if err := checkProfile(tc.profile); err != nil {
+			t.Fatal(err)
+		}
+		if value, _ := providerSetting(data, tc.profile, tc.argument, tc.configKey); value != tc.expected {
+			t.Errorf("%s/%s: expected %q, got: %q", tc.profile, tc.argument, tc.expected, value)
+		}
+	}
+	if err := checkProfile("staging"); err == nil || !strings.Contains(err.Error(), "profile staging not found") {
+		t.Fatalf("expected missing profile error, got: %v", err)
+	}
+}
+
+func TestProviderSettings(t *testing.T) {
+	defer viper.Reset()
+	os.Setenv("GRAPHIK_HOST", "graphik.env:7820")
+	defer os.Unsetenv("GRAPHIK_HOST")
+	providerSchema := Provider().(*schema.Provider).Schema
+	host, source := providerSetting(schema.TestResourceDataRaw(t, providerSchema, map[string]interface{}{}), "", "host", "host")
+	if host != "graphik.env:7820" || source != "the GRAPHIK_HOST environment variable" {
+		t.Fatalf("unexpected host %q from %q", host, source)
+	}
+	host, source = providerSetting(schema.TestResourceDataRaw(t, providerSchema, map[string]interface{}{"host": "graphik.explicit:7820"}), "", "host", "host")
+	if host != "graphik.explicit:7820" || source != "the host provider argument" {
+		t.Fatalf("unexpected host %q from %q", host, source)
+	}
+	for _, tc := range []struct {
+		raw map[string]interface{}
+		err string
+	}{
+		{raw: map[string]interface{}{}, err: "grant_type is not set: access_token is not set: set the access_token provider argument, the GRAPHIK_ACCESS_TOKEN environment variable or auth.access_token in the graphikctl config"},
+		{raw: map[string]interface{}{"grant_type": "client_credentials"}, err: "open_id is not set"},
+		{raw: map[string]interface{}{"access_token": "token", "open_id": "accounts.example.com"}, err: "open_id from the open_id provider argument must be an http(s) url"},
+	} {
+		_, err := Provider().(*schema.Provider).ConfigureFunc(schema.TestResourceDataRaw(t, providerSchema, tc.raw))
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%v: expected error containing %q, got: %v", tc.raw, tc.err, err)
+		}
+	}
+	os.Unsetenv("GRAPHIK_HOST")
+	_, err := Provider().(*schema.Provider).ConfigureFunc(schema.TestResourceDataRaw(t, providerSchema, map[string]interface{}{}))
+	if err == nil || !strings.Contains(err.Error(), "host is not set: set the host provider argument, the GRAPHIK_HOST environment variable or host in the graphikctl config") {
+		t.Fatalf("expected missing host error, got: %v", err)
+	}
+}
+
+func TestCheckConnection(t *testing.T) {
+	client := newFakeClient()
+	if err := checkConnection(context.Background(), client, "localhost:7820"); err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		err      error
+		expected string
+	}{
+		{err: status.Error(codes.Unavailable, "connection refused"), expected: "graphik at localhost:7820 is unreachable (failed to ping): connection refused"},
+		{err: status.Error(codes.Unauthenticated, "token expired"), expected: "graphik at localhost:7820 rejected the access token (failed to ping): token expired"},
+		{err: status.Error(codes.PermissionDenied, "denied"), expected: "the authenticated identity isn't authorized by graphik at localhost:7820 (failed to ping): denied"},
+	} {
+		client.err = tc.err
+		if err := checkConnection(context.Background(), client, "localhost:7820"); err == nil || err.Error() != tc.expected {
+			t.Errorf("expected %q, got: %v", tc.expected, err)
+		}
+	}
+}
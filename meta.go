@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"github.com/golang/protobuf/ptypes/empty"
+	apipb "github.com/graphikDB/graphik/gen/grpc/go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync"
+	"time"
+)
+
+// schemaKind identifies one of the lists of primitives held in a graphik schema
+type schemaKind string
+
+const (
+	indexesKind     schemaKind = "indexes"
+	triggersKind    schemaKind = "triggers"
+	constraintsKind schemaKind = "constraints"
+	authorizersKind schemaKind = "authorizers"
+)
+
+const (
+	// maxSchemaAttempts is the number of times a clobbered schema update is attempted before giving up
+	maxSchemaAttempts = 5
+	// schemaRetryBackoff is multiplied by the attempt number to get the delay before retrying a clobbered schema update
+	schemaRetryBackoff = 250 * time.Millisecond
+	// defaultRequestTimeout is the timeout of operations when neither request_timeout nor a resource timeout is set
+	defaultRequestTimeout = 5 * time.Second
+	// defaultMaxRetries is the number of times a call failing with a retryable code is retried when max_retries isn't set
+	defaultMaxRetries = 3
+	// defaultRetryBackoffMin is the delay before the first retry when retry_backoff_min isn't set
+	defaultRetryBackoffMin = 250 * time.Millisecond
+	// defaultRetryBackoffMax is the upper bound of the delay between retries when retry_backoff_max isn't set
+	defaultRetryBackoffMax = 5 * time.Second
+)
+
+// defaultRetryableCodes are the gRPC codes retried when retryable_codes isn't set:
+// graphik returns them while a node restarts or the raft cluster elects a new leader
+var defaultRetryableCodes = []codes.Code{codes.Unavailable, codes.ResourceExhausted, codes.Aborted}
+
+// providerMeta is the configured provider handed to every resource
+type providerMeta struct {
+	client graphikClient
+	// strict makes clobbered schema updates fail instead of being retried
+	strict bool
+	// requestTimeout is the timeout of operations that don't set their own
+	requestTimeout time.Duration
+	// maxRetries is the number of times a call failing with a retryable code is retried
+	maxRetries int
+	// retryBackoffMin is the delay before the first retry, doubled on every following retry
+	retryBackoffMin time.Duration
+	// retryBackoffMax is the upper bound of the delay between retries
+	retryBackoffMax time.Duration
+	// retryableCodes are the gRPC codes of the errors that are retried
+	retryableCodes map[codes.Code]bool
+	// locks serializes read-modify-write updates of each kind of schema primitive across resources
+	locks map[schemaKind]*sync.Mutex
+}
+
+func newProviderMeta(client graphikClient) *providerMeta {
+	retryableCodes := map[codes.Code]bool{}
+	for _, code := range defaultRetryableCodes {
+		retryableCodes[code] = true
+	}
+	return &providerMeta{
+		client:          client,
+		requestTimeout:  defaultRequestTimeout,
+		maxRetries:      defaultMaxRetries,
+		retryBackoffMin: defaultRetryBackoffMin,
+		retryBackoffMax: defaultRetryBackoffMax,
+		retryableCodes:  retryableCodes,
+		locks: map[schemaKind]*sync.Mutex{
+			indexesKind:     {},
+			triggersKind:    {},
+			constraintsKind: {},
+			authorizersKind: {},
+		},
+	}
+}
+
+// resourceTimeouts declares the create/read/update/delete timeouts supported by every resource.
+// The zero defaults mean the timeout isn't set & fall back to the provider's request_timeout.
+func resourceTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(time.Duration(0)),
+		Read:   schema.DefaultTimeout(time.Duration(0)),
+		Update: schema.DefaultTimeout(time.Duration(0)),
+		Delete: schema.DefaultTimeout(time.Duration(0)),
+	}
+}
+
+// timeout returns the timeout of the given operation on the resource, falling back to the provider's request_timeout
+func (m *providerMeta) timeout(data *schema.ResourceData, key string) time.Duration {
+	if data.Id() != "" {
+		// the SDK returns its 20 minute default when the state has no timeouts meta, ex: right after an import or
+		// for state written before timeouts were supported, so only trust timeouts actually held by the state
+		state := data.State()
+		timeouts, _ := state.Meta[schema.TimeoutKey].(map[string]interface{})
+		if _, ok := timeouts[key]; !ok {
+			return m.requestTimeout
+		}
+	}
+	if timeout := data.Timeout(key); timeout > 0 {
+		return timeout
+	}
+	return m.requestTimeout
+}
+
+// updateSchema performs a read-modify-write update of the given kind of schema primitive.
+// mutate changes the fetched schema before it is written back & applied reports whether the change is reflected by the server.
+// The schema is re-fetched after every write and the update is retried if a concurrent writer clobbered it (unless strict).
+func (m *providerMeta) updateSchema(ctx context.Context, kind schemaKind, mutate func(scheme *apipb.Schema), applied func(scheme *apipb.Schema) bool) error {
+	lock := m.locks[kind]
+	lock.Lock()
+	defer lock.Unlock()
+	for attempt := 1; ; attempt++ {
+		scheme, err := m.getSchema(ctx)
+		if err != nil {
+			return err
+		}
+		if applied(scheme) {
+			return nil
+		}
+		mutate(scheme)
+		if err := m.retry(ctx, func() error {
+			return m.setSchema(ctx, kind, scheme)
+		}); err != nil {
+			return err
+		}
+		scheme, err = m.getSchema(ctx)
+		if err != nil {
+			return err
+		}
+		if applied(scheme) {
+			return nil
+		}
+		if m.strict {
+			return errors.Errorf("%s were concurrently modified: change was overwritten (strict_concurrency is enabled)", kind)
+		}
+		if attempt >= maxSchemaAttempts {
+			return errors.Errorf("%s were concurrently modified: change was overwritten %v times", kind, attempt)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * schemaRetryBackoff):
+		}
+	}
+}
+
+// setSchema writes the given kind of schema primitive from the schema back to the server
+func (m *providerMeta) setSchema(ctx context.Context, kind schemaKind, scheme *apipb.Schema) error {
+	switch kind {
+	case indexesKind:
+		return m.client.SetIndexes(ctx, &apipb.Indexes{Indexes: scheme.GetIndexes().GetIndexes()})
+	case triggersKind:
+		return m.client.SetTriggers(ctx, &apipb.Triggers{Triggers: scheme.GetTriggers().GetTriggers()})
+	case constraintsKind:
+		return m.client.SetConstraints(ctx, &apipb.Constraints{Constraints: scheme.GetConstraints().GetConstraints()})
+	case authorizersKind:
+		return m.client.SetAuthorizers(ctx, &apipb.Authorizers{Authorizers: scheme.GetAuthorizers().GetAuthorizers()})
+	default:
+		return errors.Errorf("unsupported schema kind: %s", kind)
+	}
+}
+
+// getSchema fetches the schema, retrying transient failures
+func (m *providerMeta) getSchema(ctx context.Context) (*apipb.Schema, error) {
+	var scheme *apipb.Schema
+	err := m.retry(ctx, func() error {
+		var err error
+		scheme, err = m.client.GetSchema(ctx, &empty.Empty{})
+		return err
+	})
+	return scheme, err
+}
+
+// classify reports whether the error of a call to graphik is retryable according to the retry policy
+func (m *providerMeta) classify(err error) *resource.RetryError {
+	if m.retryableCodes[status.Code(err)] {
+		return resource.RetryableError(err)
+	}
+	return resource.NonRetryableError(err)
+}
+
+// backoff returns the delay before the given retry: retryBackoffMin doubled on every retry, capped at retryBackoffMax
+func (m *providerMeta) backoff(retry int) time.Duration {
+	delay := m.retryBackoffMin
+	for i := 1; i < retry && delay < m.retryBackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > m.retryBackoffMax {
+		return m.retryBackoffMax
+	}
+	return delay
+}
+
+// retry calls f until it succeeds, fails with a non-retryable error, exhausts maxRetries or ctx is done.
+// The policy is applied here rather than through resource.Retry because resource.Retry polls on its own
+// fixed 500ms-10s schedule, which would override retry_backoff_min & retry_backoff_max.
+func (m *providerMeta) retry(ctx context.Context, f func() error) error {
+	for retry := 1; ; retry++ {
+		result := m.classify(f())
+		if result == nil {
+			return nil
+		}
+		if !result.Retryable {
+			return result.Err
+		}
+		if retry > m.maxRetries {
+			return errors.Wrapf(result.Err, "giving up after %v retries", m.maxRetries)
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(result.Err, ctx.Err().Error())
+		case <-time.After(m.backoff(retry)):
+		}
+	}
+}
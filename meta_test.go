@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	apipb "github.com/graphikDB/graphik/gen/grpc/go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestResourceTimeouts(t *testing.T) {
+	client := newFakeClient()
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testProviders(client),
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+resource "graphik_index" "default" {
+  name               = "default"
+  gtype              = "task"
+  expression         = "true"
+  target_docs        = true
+  target_connections = false
+}
+
+resource "graphik_index" "slow" {
+  name               = "slow"
+  gtype              = "task"
+  expression         = "true"
+  target_docs        = true
+  target_connections = false
+  timeouts {
+    create = "10m"
+  }
+}
+`,
+				Check: func(state *terraform.State) error {
+					expected := map[string]time.Duration{
+						"graphik_index.default": 0,
+						"graphik_index.slow":    10 * time.Minute,
+					}
+					for name, timeout := range expected {
+						timeouts := state.RootModule().Resources[name].Primary.Meta[schema.TimeoutKey].(map[string]interface{})
+						if create := time.Duration(timeouts[schema.TimeoutCreate].(float64)); create != timeout {
+							return errors.Errorf("expected %s create timeout of %v, got: %v", name, timeout, create)
+						}
+					}
+					return nil
+				},
+			},
+		},
+	})
+}
+
+func TestResourceTimeouts_noMeta(t *testing.T) {
+	client := newFakeClient()
+	client.docs["role/admin"] = &apipb.Doc{Ref: &apipb.Ref{Gtype: "role", Gid: "admin"}}
+	meta := newProviderMeta(client)
+	meta.requestTimeout = 3 * time.Second
+	for _, tc := range []struct {
+		name     string
+		meta     map[string]interface{}
+		expected time.Duration
+	}{
+		// ex: right after an import or state written before timeouts were supported
+		{name: "no timeouts meta", expected: meta.requestTimeout},
+		{name: "unset timeouts", meta: map[string]interface{}{
+			schema.TimeoutKey: map[string]interface{}{schema.TimeoutRead: float64(0)},
+		}, expected: meta.requestTimeout},
+		{name: "read timeout", meta: map[string]interface{}{
+			schema.TimeoutKey: map[string]interface{}{schema.TimeoutRead: float64(time.Minute)},
+		}, expected: time.Minute},
+	} {
+		state := &terraform.InstanceState{
+			ID:         "role/admin",
+			Attributes: map[string]string{"id": "role/admin", "gtype": "role", "gid": "admin", "attributes": "{}"},
+			Meta:       tc.meta,
+		}
+		start := time.Now()
+		if _, err := resourceDoc().Refresh(state, meta); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if timeout := client.getDocDeadline.Sub(start); timeout < tc.expected || timeout > tc.expected+time.Second {
+			t.Errorf("%s: expected a timeout of %v, got: %v", tc.name, tc.expected, timeout)
+		}
+	}
+}
+
+func TestRetry(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "no leader")
+	for _, tc := range []struct {
+		errs  []error
+		calls int
+		err   string
+	}{
+		{errs: []error{unavailable, unavailable}, calls: 3},
+		{errs: []error{status.Error(codes.PermissionDenied, "denied")}, calls: 1, err: "denied"},
+		{errs: []error{unavailable, unavailable, unavailable, unavailable}, calls: 4, err: "giving up after 3 retries"},
+	} {
+		client := newFakeClient()
+		client.schemaErrs = tc.errs
+		meta := newProviderMeta(client)
+		meta.retryBackoffMin = time.Millisecond
+		_, err := meta.getSchema(context.Background())
+		switch {
+		case tc.err == "" && err != nil:
+			t.Errorf("unexpected error: %s", err)
+		case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+			t.Errorf("expected error containing %q, got: %v", tc.err, err)
+		}
+		if client.getSchemaCalls != tc.calls {
+			t.Errorf("expected %v calls, got: %v", tc.calls, client.getSchemaCalls)
+		}
+	}
+	meta := newProviderMeta(newFakeClient())
+	meta.retryBackoffMin, meta.retryBackoffMax = 100*time.Millisecond, time.Second
+	for retry, expected := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 4: 800 * time.Millisecond, 5: time.Second, 50: time.Second} {
+		if backoff := meta.backoff(retry); backoff != expected {
+			t.Errorf("retry %v: expected backoff %s, got: %s", retry, expected, backoff)
+		}
+	}
+}
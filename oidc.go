@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	// registers SHA-384 & SHA-512 so RS384 & RS512 signatures can be verified
+	_ "crypto/sha512"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// httpTimeout is the timeout of every request to the identity provider
+	httpTimeout = 15 * time.Second
+	// oidcCacheTTL is how long downloaded open id connect metadata & signing keys are reused before being downloaded again
+	oidcCacheTTL = 24 * time.Hour
+	// wellKnownSuffix is the path the open id connect metadata is served from, relative to the issuer
+	wellKnownSuffix = "/.well-known/openid-configuration"
+)
+
+// oidcMetadata is the subset of an identity provider's open id connect metadata used by the provider
+type oidcMetadata struct {
+	Issuer        string `json:"issuer"`
+	TokenEndpoint string `json:"token_endpoint"`
+	JwksURI       string `json:"jwks_uri"`
+}
+
+// jsonWebKeys is a JSON web key set holding the keys the identity provider signs tokens with
+type jsonWebKeys struct {
+	Keys []struct {
+		Kid string `json:"kid"`
+		Kty string `json:"kty"`
+		N   string `json:"n"`
+		E   string `json:"e"`
+	} `json:"keys"`
+}
+
+// newHTTPClient returns the client used for every request to the identity provider
+func newHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	return &http.Client{
+		Timeout:   httpTimeout,
+		Transport: transport,
+	}
+}
+
+// oidcCacheDir returns the directory downloaded open id connect documents are cached in, or "" if there is no cache directory
+func oidcCacheDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "terraform-provider-graphik")
+}
+
+// getJSON decodes the JSON document served at uri into out. Documents are cached in cacheDir (unless empty) for oidcCacheTTL.
+// refresh bypasses the cached document, which is replaced by the downloaded one.
+func getJSON(ctx context.Context, client *http.Client, uri, cacheDir string, refresh bool, out interface{}) error {
+	var cached string
+	if cacheDir != "" {
+		sum := sha256.Sum256([]byte(uri))
+		cached = filepath.Join(cacheDir, hex.EncodeToString(sum[:])+".json")
+		if info, err := os.Stat(cached); err == nil && !refresh && time.Since(info.ModTime()) < oidcCacheTTL {
+			if bits, err := ioutil.ReadFile(cached); err == nil && json.Unmarshal(bits, out) == nil {
+				return nil
+			}
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("%s returned status %s", uri, resp.Status)
+	}
+	bits, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(bits, out); err != nil {
+		return errors.Wrapf(err, "%s returned invalid json", uri)
+	}
+	if cached != "" {
+		// caching is best effort: the document was downloaded either way
+		if err := os.MkdirAll(cacheDir, 0700); err == nil {
+			_ = ioutil.WriteFile(cached, bits, 0600)
+		}
+	}
+	return nil
+}
+
+// fetchMetadata downloads the open id connect metadata served at uri and checks that it belongs to the issuer it was discovered from
+func fetchMetadata(ctx context.Context, client *http.Client, uri, cacheDir string) (*oidcMetadata, error) {
+	metadata := &oidcMetadata{}
+	if err := getJSON(ctx, client, uri, cacheDir, false, metadata); err != nil {
+		return nil, errors.Wrap(err, "failed to get oidc metadata")
+	}
+	if metadata.Issuer == "" {
+		return nil, errors.Errorf("oidc metadata %s has no issuer", uri)
+	}
+	if strings.HasSuffix(uri, wellKnownSuffix) {
+		if expected := strings.TrimSuffix(uri, wellKnownSuffix); strings.TrimSuffix(metadata.Issuer, "/") != strings.TrimSuffix(expected, "/") {
+			return nil, errors.Errorf("oidc metadata %s belongs to issuer %s, expected %s", uri, metadata.Issuer, expected)
+		}
+	}
+	return metadata, nil
+}
+
+// verifyAccessToken checks that the access token hasn't expired and, when the metadata is known, that it was issued & signed
+// by the identity provider. Opaque (non-JWT) access tokens can't be inspected & are left to graphik to verify.
+func verifyAccessToken(ctx context.Context, client *http.Client, token string, metadata *oidcMetadata, cacheDir string) error {
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		return nil
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	var claims struct {
+		Iss string `json:"iss"`
+		Exp int64  `json:"exp"`
+	}
+	if err := decodeSegment(segments[0], &header); err != nil {
+		return errors.Wrap(err, "failed to decode access_token header")
+	}
+	if err := decodeSegment(segments[1], &claims); err != nil {
+		return errors.Wrap(err, "failed to decode access_token claims")
+	}
+	if claims.Exp != 0 && time.Now().Unix() >= claims.Exp {
+		return errors.Errorf("access_token expired at %s: log in again or configure grant_type so tokens are refreshed", time.Unix(claims.Exp, 0).UTC().Format(time.RFC3339))
+	}
+	if metadata == nil {
+		return nil
+	}
+	if claims.Iss != "" && claims.Iss != metadata.Issuer {
+		return errors.Errorf("access_token was issued by %s, expected %s", claims.Iss, metadata.Issuer)
+	}
+	hash, ok := map[string]crypto.Hash{"RS256": crypto.SHA256, "RS384": crypto.SHA384, "RS512": crypto.SHA512}[header.Alg]
+	if !ok || metadata.JwksURI == "" {
+		// only RSA signatures are verified by the provider
+		return nil
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(segments[2])
+	if err != nil {
+		return errors.Wrap(err, "failed to decode access_token signature")
+	}
+	digest := hash.New()
+	digest.Write([]byte(segments[0] + "." + segments[1]))
+	// the cached keys are stale when the identity provider rotated its keys, so they're downloaded again once before giving up
+	for _, refresh := range []bool{false, true} {
+		keys := jsonWebKeys{}
+		if err := getJSON(ctx, client, metadata.JwksURI, cacheDir, refresh, &keys); err != nil {
+			return errors.Wrap(err, "failed to get oidc signing keys")
+		}
+		if verifySignature(keys, header.Kid, hash, digest.Sum(nil), signature) {
+			return nil
+		}
+		if cacheDir == "" {
+			// the keys were just downloaded
+			break
+		}
+	}
+	return errors.Errorf("access_token isn't signed by any key of %s", metadata.JwksURI)
+}
+
+// verifySignature reports whether the signature of the digest was made by the RSA key of the set with the given kid,
+// or by any RSA key of the set when kid is empty
+func verifySignature(keys jsonWebKeys, kid string, hash crypto.Hash, digest, signature []byte) bool {
+	for _, key := range keys.Keys {
+		if key.Kty != "RSA" || (kid != "" && key.Kid != kid) {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(key.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(key.E)
+		if err != nil {
+			continue
+		}
+		publicKey := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if rsa.VerifyPKCS1v15(publicKey, hash, digest, signature) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeSegment decodes a base64url encoded JSON segment of a JWT into out
+func decodeSegment(segment string, out interface{}) error {
+	bits, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bits, out)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFetchMetadata(t *testing.T) {
+	requests := 0
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		issuer := server.URL
+		if r.URL.Path == "/other"+wellKnownSuffix {
+			issuer = "https://accounts.example.com"
+		}
+		fmt.Fprintf(w, `{"issuer": %q, "token_endpoint": %q}`, issuer, server.URL+"/token")
+	}))
+	defer server.Close()
+	cacheDir := t.TempDir()
+	for i := 0; i < 2; i++ {
+		metadata, err := fetchMetadata(context.Background(), server.Client(), server.URL+wellKnownSuffix, cacheDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if metadata.TokenEndpoint != server.URL+"/token" {
+			t.Fatalf("unexpected token endpoint: %s", metadata.TokenEndpoint)
+		}
+	}
+	if requests != 1 {
+		t.Fatalf("expected the cached metadata to be reused, got %v requests", requests)
+	}
+	if _, err := fetchMetadata(context.Background(), server.Client(), server.URL+"/other"+wellKnownSuffix, ""); err == nil || !strings.Contains(err.Error(), "belongs to issuer https://accounts.example.com") {
+		t.Fatalf("expected issuer mismatch, got: %v", err)
+	}
+}
+
+func TestVerifyAccessToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"keys": [{"kid": "1", "kty": "RSA", "n": %q, "e": "AQAB"}]}`, base64.RawURLEncoding.EncodeToString(key.N.Bytes()))
+	}))
+	defer server.Close()
+	metadata := &oidcMetadata{Issuer: "https://accounts.example.com", JwksURI: server.URL}
+	sign := func(key *rsa.PrivateKey, iss string, exp time.Time) string {
+		payload := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RS256","kid":"1"}`)) + "." +
+			base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"iss":%q,"exp":%v}`, iss, exp.Unix())))
+		digest := sha256.Sum256([]byte(payload))
+		signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return payload + "." + base64.RawURLEncoding.EncodeToString(signature)
+	}
+	for _, tc := range []struct {
+		token    string
+		metadata *oidcMetadata
+		err      string
+	}{
+		{token: "opaque", metadata: metadata},
+		{token: sign(key, metadata.Issuer, time.Now().Add(time.Hour)), metadata: metadata},
+		{token: sign(otherKey, metadata.Issuer, time.Now().Add(time.Hour)), metadata: nil},
+		{token: sign(key, metadata.Issuer, time.Now().Add(-time.Hour)), metadata: nil, err: "access_token expired"},
+		{token: sign(key, "https://evil.example.com", time.Now().Add(time.Hour)), metadata: metadata, err: "issued by https://evil.example.com"},
+		{token: sign(otherKey, metadata.Issuer, time.Now().Add(time.Hour)), metadata: metadata, err: "isn't signed by any key"},
+	} {
+		err := verifyAccessToken(context.Background(), server.Client(), tc.token, tc.metadata, "")
+		switch {
+		case tc.err == "" && err != nil:
+			t.Errorf("unexpected error: %s", err)
+		case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+			t.Errorf("expected error containing %q, got: %v", tc.err, err)
+		}
+	}
+}
+
+func TestVerifyAccessToken_rotatedKeys(t *testing.T) {
+	keys := map[string]*rsa.PrivateKey{}
+	for _, kid := range []string{"1", "2"} {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[kid] = key
+	}
+	var (
+		mu      sync.Mutex
+		current = "1"
+		fetches int
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		fetches++
+		fmt.Fprintf(w, `{"keys": [{"kid": %q, "kty": "RSA", "n": %q, "e": "AQAB"}]}`, current, base64.RawURLEncoding.EncodeToString(keys[current].N.Bytes()))
+	}))
+	defer server.Close()
+	metadata := &oidcMetadata{Issuer: "https://accounts.example.com", JwksURI: server.URL}
+	sign := func(kid string) string {
+		payload := base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"alg":"RS512","kid":%q}`, kid))) + "." +
+			base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf(`{"iss":%q,"exp":%v}`, metadata.Issuer, time.Now().Add(time.Hour).Unix())))
+		digest := sha512.Sum512([]byte(payload))
+		signature, err := rsa.SignPKCS1v15(rand.Reader, keys[kid], crypto.SHA512, digest[:])
+		if err != nil {
+			t.Fatal(err)
+		}
+		return payload + "." + base64.RawURLEncoding.EncodeToString(signature)
+	}
+	cacheDir := t.TempDir()
+	for _, tc := range []struct {
+		current string
+		kid     string
+		fetches int
+		err     string
+	}{
+		// the keys are downloaded & cached
+		{current: "1", kid: "1", fetches: 1},
+		// the cached keys are reused
+		{current: "1", kid: "1", fetches: 1},
+		// the identity provider rotated its keys: the cache is bypassed once
+		{current: "2", kid: "2", fetches: 2},
+		// the refreshed keys are cached
+		{current: "2", kid: "2", fetches: 2},
+		// a retired key is rejected after downloading the keys again
+		{current: "2", kid: "1", fetches: 3, err: "isn't signed by any key"},
+	} {
+		mu.Lock()
+		current = tc.current
+		mu.Unlock()
+		err := verifyAccessToken(context.Background(), server.Client(), sign(tc.kid), metadata, cacheDir)
+		switch {
+		case tc.err == "" && err != nil:
+			t.Errorf("kid %s: unexpected error: %s", tc.kid, err)
+		case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+			t.Errorf("kid %s: expected error containing %q, got: %v", tc.kid, tc.err, err)
+		}
+		mu.Lock()
+		if fetches != tc.fetches {
+			t.Errorf("kid %s: expected %d downloads of the keys, got: %d", tc.kid, tc.fetches, fetches)
+		}
+		mu.Unlock()
+	}
+}
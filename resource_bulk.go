@@ -0,0 +1,340 @@
+package main
+
+import (
+	"context"
+	apipb "github.com/graphikDB/graphik/gen/grpc/go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/pkg/errors"
+	"reflect"
+	"sort"
+)
+
+// bulkKind adapts one kind of schema primitive to an authoritative resource that owns the entire list
+type bulkKind struct {
+	kind schemaKind
+	// block is the name of the nested block declaring a single primitive
+	block string
+	// attributes returns the computed attributes of a single primitive
+	attributes func() map[string]*schema.Schema
+	// flatten returns every primitive of the kind held in the schema
+	flatten func(scheme *apipb.Schema) []interface{}
+	// expand replaces every primitive of the kind held in the schema with the declared primitives
+	expand func(scheme *apipb.Schema, values []interface{})
+}
+
+var (
+	bulkIndexes = bulkKind{
+		kind:       indexesKind,
+		block:      "index",
+		attributes: indexAttributes,
+		flatten: func(scheme *apipb.Schema) []interface{} {
+			var values []interface{}
+			for _, v := range scheme.GetIndexes().GetIndexes() {
+				values = append(values, flattenIndex(v))
+			}
+			return values
+		},
+		expand: func(scheme *apipb.Schema, values []interface{}) {
+			var indexes []*apipb.Index
+			for _, v := range values {
+				indexes = append(indexes, expandIndex(v.(map[string]interface{})))
+			}
+			scheme.Indexes = &apipb.Indexes{Indexes: indexes}
+		},
+	}
+	bulkTriggers = bulkKind{
+		kind:       triggersKind,
+		block:      "trigger",
+		attributes: triggerAttributes,
+		flatten: func(scheme *apipb.Schema) []interface{} {
+			var values []interface{}
+			for _, v := range scheme.GetTriggers().GetTriggers() {
+				values = append(values, flattenTrigger(v))
+			}
+			return values
+		},
+		expand: func(scheme *apipb.Schema, values []interface{}) {
+			var triggers []*apipb.Trigger
+			for _, v := range values {
+				triggers = append(triggers, expandTrigger(v.(map[string]interface{})))
+			}
+			scheme.Triggers = &apipb.Triggers{Triggers: triggers}
+		},
+	}
+	bulkConstraints = bulkKind{
+		kind:       constraintsKind,
+		block:      "constraint",
+		attributes: constraintAttributes,
+		flatten: func(scheme *apipb.Schema) []interface{} {
+			var values []interface{}
+			for _, v := range scheme.GetConstraints().GetConstraints() {
+				values = append(values, flattenConstraint(v))
+			}
+			return values
+		},
+		expand: func(scheme *apipb.Schema, values []interface{}) {
+			var constraints []*apipb.Constraint
+			for _, v := range values {
+				constraints = append(constraints, expandConstraint(v.(map[string]interface{})))
+			}
+			scheme.Constraints = &apipb.Constraints{Constraints: constraints}
+		},
+	}
+	bulkAuthorizers = bulkKind{
+		kind:       authorizersKind,
+		block:      "authorizer",
+		attributes: authorizerAttributes,
+		flatten: func(scheme *apipb.Schema) []interface{} {
+			var values []interface{}
+			for _, v := range scheme.GetAuthorizers().GetAuthorizers() {
+				values = append(values, flattenAuthorizer(v))
+			}
+			return values
+		},
+		expand: func(scheme *apipb.Schema, values []interface{}) {
+			var authorizers []*apipb.Authorizer
+			for _, v := range values {
+				authorizers = append(authorizers, expandAuthorizer(v.(map[string]interface{})))
+			}
+			scheme.Authorizers = &apipb.Authorizers{Authorizers: authorizers}
+		},
+	}
+)
+
+// resourceBulk returns an authoritative resource that owns every primitive of the given kind:
+// primitives that exist on the server but aren't declared are removed
+func resourceBulk(b bulkKind, description string) *schema.Resource {
+	write := func(data *schema.ResourceData, i interface{}) error {
+		meta := i.(*providerMeta)
+		timeout := meta.timeout(data, schema.TimeoutUpdate)
+		if data.IsNewResource() {
+			timeout = meta.timeout(data, schema.TimeoutCreate)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		declared := data.Get(b.block).(*schema.Set).List()
+		err := meta.updateSchema(ctx, b.kind, func(scheme *apipb.Schema) {
+			b.expand(scheme, declared)
+		}, func(scheme *apipb.Schema) bool {
+			return equalPrimitives(b.flatten(scheme), declared)
+		})
+		if err != nil {
+			return err
+		}
+		for _, k := range []string{"added", "changed", "removed"} {
+			// an empty planned list is dropped from state unless it's set explicitly, which shows it as unknown on the next plan
+			if err := data.Set(k, data.Get(k)); err != nil {
+				return err
+			}
+		}
+		data.SetId(string(b.kind))
+		return nil
+	}
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			b.block: {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "declares a single " + b.block + ". every " + b.block + " that isn't declared is removed from the server",
+				Elem:        &schema.Resource{Schema: requiredAttributes(b.attributes())},
+			},
+			"added": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "names of the " + string(b.kind) + " added by the pending change",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"changed": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "names of the " + string(b.kind) + " changed by the pending change",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"removed": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "names of the " + string(b.kind) + " removed by the pending change",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+		Create: write,
+		Read: func(data *schema.ResourceData, i interface{}) error {
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutRead))
+			defer cancel()
+			scheme, err := meta.getSchema(ctx)
+			if err != nil {
+				return err
+			}
+			if err := data.Set(b.block, b.flatten(scheme)); err != nil {
+				return err
+			}
+			for _, k := range []string{"added", "changed", "removed"} {
+				if err := data.Set(k, []string{}); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Update: write,
+		Delete: func(data *schema.ResourceData, i interface{}) error {
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutDelete))
+			defer cancel()
+			return meta.updateSchema(ctx, b.kind, func(scheme *apipb.Schema) {
+				b.expand(scheme, nil)
+			}, func(scheme *apipb.Schema) bool {
+				return len(b.flatten(scheme)) == 0
+			})
+		},
+		CustomizeDiff: func(diff *schema.ResourceDiff, i interface{}) error {
+			// a new resource always diffs against the server: declaring no blocks at all removes every existing primitive
+			if diff.Id() != "" && !diff.HasChange(b.block) {
+				return nil
+			}
+			before, after := diff.GetChange(b.block)
+			existing := before.(*schema.Set).List()
+			declared := after.(*schema.Set).List()
+			if name := duplicatePrimitive(declared); name != "" {
+				return errors.Errorf("%s %s is declared more than once", b.block, name)
+			}
+			if diff.Id() == "" && i != nil {
+				// nothing is in state yet, so diff against what already exists on the server
+				meta := i.(*providerMeta)
+				ctx, cancel := context.WithTimeout(context.Background(), meta.requestTimeout)
+				defer cancel()
+				scheme, err := meta.getSchema(ctx)
+				if err != nil {
+					return err
+				}
+				existing = b.flatten(scheme)
+			}
+			added, changed, removed := diffPrimitives(existing, declared)
+			if err := diff.SetNew("added", added); err != nil {
+				return err
+			}
+			if err := diff.SetNew("changed", changed); err != nil {
+				return err
+			}
+			return diff.SetNew("removed", removed)
+		},
+		Importer:    importBulk(b),
+		Timeouts:    resourceTimeouts(),
+		Description: description,
+	}
+}
+
+// requiredAttributes makes every attribute of the given computed attributes a required argument
+func requiredAttributes(attributes map[string]*schema.Schema) map[string]*schema.Schema {
+	for name, attr := range attributes {
+		attr.Computed = false
+		attr.Required = true
+		switch {
+		case name == "expression":
+			attr.ValidateFunc = validateCEL
+		case name == "trigger":
+			attr.ValidateFunc = validateCELMap
+		case name == "method":
+			attr.ValidateFunc = validateMethod
+		case attr.Type == schema.TypeString:
+			attr.ValidateFunc = validation.StringIsNotEmpty
+		}
+	}
+	return attributes
+}
+
+// primitivesByName indexes flattened primitives by their name
+func primitivesByName(values []interface{}) map[string]map[string]interface{} {
+	byName := map[string]map[string]interface{}{}
+	for _, v := range values {
+		m := v.(map[string]interface{})
+		byName[m["name"].(string)] = m
+	}
+	return byName
+}
+
+// diffPrimitives returns the sorted names of the flattened primitives added, changed & removed between before & after
+func diffPrimitives(before, after []interface{}) ([]string, []string, []string) {
+	var added, changed, removed = []string{}, []string{}, []string{}
+	beforeByName := primitivesByName(before)
+	afterByName := primitivesByName(after)
+	for name, v := range afterByName {
+		previous, ok := beforeByName[name]
+		if !ok {
+			added = append(added, name)
+		} else if !reflect.DeepEqual(previous, v) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range beforeByName {
+		if _, ok := afterByName[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(changed)
+	sort.Strings(removed)
+	return added, changed, removed
+}
+
+// duplicatePrimitive returns the first name shared by more than one of the flattened primitives
+func duplicatePrimitive(values []interface{}) string {
+	names := map[string]struct{}{}
+	for _, v := range values {
+		name := v.(map[string]interface{})["name"].(string)
+		if _, ok := names[name]; ok {
+			return name
+		}
+		names[name] = struct{}{}
+	}
+	return ""
+}
+
+// equalPrimitives reports whether both lists hold the same flattened primitives regardless of order
+func equalPrimitives(a, b []interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	return reflect.DeepEqual(primitivesByName(a), primitivesByName(b))
+}
+
+func expandIndex(m map[string]interface{}) *apipb.Index {
+	return &apipb.Index{
+		Name:              m["name"].(string),
+		Gtype:             m["gtype"].(string),
+		Expression:        m["expression"].(string),
+		TargetDocs:        m["target_docs"].(bool),
+		TargetConnections: m["target_connections"].(bool),
+	}
+}
+
+func expandTrigger(m map[string]interface{}) *apipb.Trigger {
+	return &apipb.Trigger{
+		Name:              m["name"].(string),
+		Gtype:             m["gtype"].(string),
+		Trigger:           triggerArrow(m["expression"].(string), m["trigger"].(string)),
+		TargetDocs:        m["target_docs"].(bool),
+		TargetConnections: m["target_connections"].(bool),
+	}
+}
+
+func expandConstraint(m map[string]interface{}) *apipb.Constraint {
+	return &apipb.Constraint{
+		Name:              m["name"].(string),
+		Gtype:             m["gtype"].(string),
+		Expression:        m["expression"].(string),
+		TargetDocs:        m["target_docs"].(bool),
+		TargetConnections: m["target_connections"].(bool),
+	}
+}
+
+func expandAuthorizer(m map[string]interface{}) *apipb.Authorizer {
+	return &apipb.Authorizer{
+		Name:            m["name"].(string),
+		Method:          m["method"].(string),
+		Expression:      m["expression"].(string),
+		TargetRequests:  m["target_requests"].(bool),
+		TargetResponses: m["target_responses"].(bool),
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	apipb "github.com/graphikDB/graphik/gen/grpc/go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/pkg/errors"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestAccBulkIndexes(t *testing.T) {
+	client := newFakeClient()
+	client.seed()
+	config := testProviderConfig + `
+resource "graphik_indexes" "all" {
+  index {
+    name               = "b"
+    gtype              = "task"
+    expression         = "this.attributes.priority == 'low'"
+    target_docs        = true
+    target_connections = false
+  }
+  index {
+    name               = "d"
+    gtype              = "task"
+    expression         = "true"
+    target_docs        = true
+    target_connections = false
+  }
+}
+`
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testProviders(client),
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("graphik_indexes.all", "added.#", "1"),
+					resource.TestCheckResourceAttr("graphik_indexes.all", "added.0", "d"),
+					resource.TestCheckResourceAttr("graphik_indexes.all", "changed.#", "1"),
+					resource.TestCheckResourceAttr("graphik_indexes.all", "changed.0", "b"),
+					resource.TestCheckResourceAttr("graphik_indexes.all", "removed.#", "2"),
+					resource.TestCheckResourceAttr("graphik_indexes.all", "removed.1", "c"),
+					func(state *terraform.State) error {
+						names := client.names("graphik_index")
+						sort.Strings(names)
+						if !reflect.DeepEqual(names, []string{"b", "d"}) {
+							return errors.Errorf("expected only declared indexes to remain, got: %v", names)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccBulkIndexes_empty(t *testing.T) {
+	client := newFakeClient()
+	client.seed()
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testProviders(client),
+		Steps: []resource.TestStep{
+			{
+				Config: testProviderConfig + `
+resource "graphik_indexes" "all" {}
+`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("graphik_indexes.all", "added.#", "0"),
+					resource.TestCheckResourceAttr("graphik_indexes.all", "removed.#", "3"),
+					resource.TestCheckResourceAttr("graphik_indexes.all", "removed.0", "a"),
+					resource.TestCheckResourceAttr("graphik_indexes.all", "removed.2", "c"),
+					func(state *terraform.State) error {
+						if names := client.names("graphik_index"); len(names) != 0 {
+							return errors.Errorf("expected every index to be removed, got: %v", names)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestDiffPrimitives(t *testing.T) {
+	before := []interface{}{
+		flattenIndex(&apipb.Index{Name: "a", Expression: "true"}),
+		flattenIndex(&apipb.Index{Name: "b", Expression: "true"}),
+	}
+	after := []interface{}{
+		flattenIndex(&apipb.Index{Name: "b", Expression: "false"}),
+		flattenIndex(&apipb.Index{Name: "c", Expression: "true"}),
+	}
+	added, changed, removed := diffPrimitives(before, after)
+	if !reflect.DeepEqual(added, []string{"c"}) || !reflect.DeepEqual(changed, []string{"b"}) || !reflect.DeepEqual(removed, []string{"a"}) {
+		t.Fatalf("unexpected diff: added=%v changed=%v removed=%v", added, changed, removed)
+	}
+	if name := duplicatePrimitive(append(after, flattenIndex(&apipb.Index{Name: "c"}))); name != "c" {
+		t.Fatalf("expected duplicate c, got: %q", name)
+	}
+}
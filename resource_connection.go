@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	apipb "github.com/graphikDB/graphik/gen/grpc/go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// resourceConnection manages a single connection(edge) between two docs, ex: user -> member_of -> team
+func resourceConnection() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"gtype": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "the type of the connection ex: member_of",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"gid": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "the unique id of the connection within its type. generated by graphik when unset",
+			},
+			"from": refSchema("the doc the connection starts from", true),
+			"to":   refSchema("the doc the connection points to", true),
+			"directed": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				ForceNew:    true,
+				Description: "whether the connection only points from -> to",
+			},
+			"attributes": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "{}",
+				Description:      "JSON encoded object of the connection's attributes - use jsonencode() to build it. attributes added to the connection outside of terraform (ex: by a trigger) are ignored",
+				ValidateFunc:     validateAttributes,
+				DiffSuppressFunc: suppressEquivalentAttributes,
+			},
+		},
+		Create: func(data *schema.ResourceData, i interface{}) error {
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutCreate))
+			defer cancel()
+			attributes, err := parseAttributes(data.Get("attributes").(string))
+			if err != nil {
+				return err
+			}
+			// CreateConnection isn't retried for the same reasons as CreateDoc
+			connection, err := meta.client.CreateConnection(ctx, &apipb.ConnectionConstructor{
+				Ref: &apipb.RefConstructor{
+					Gtype: data.Get("gtype").(string),
+					Gid:   data.Get("gid").(string),
+				},
+				Attributes: attributes,
+				Directed:   data.Get("directed").(bool),
+				From:       expandRef(data.Get("from")),
+				To:         expandRef(data.Get("to")),
+			})
+			if err != nil {
+				return err
+			}
+			data.SetId(refID(connection.GetRef()))
+			return setConnectionData(data, connection)
+		},
+		Read: func(data *schema.ResourceData, i interface{}) error {
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutRead))
+			defer cancel()
+			ref, err := parseRefID(data.Id())
+			if err != nil {
+				return err
+			}
+			var connection *apipb.Connection
+			err = meta.retry(ctx, func() error {
+				var err error
+				connection, err = meta.client.GetConnection(ctx, ref)
+				return err
+			})
+			if status.Code(err) == codes.NotFound {
+				// the connection was removed outside of terraform
+				data.SetId("")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			return setConnectionData(data, connection)
+		},
+		Update: func(data *schema.ResourceData, i interface{}) error {
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutUpdate))
+			defer cancel()
+			ref, err := parseRefID(data.Id())
+			if err != nil {
+				return err
+			}
+			var connection *apipb.Connection
+			err = meta.retry(ctx, func() error {
+				var err error
+				connection, err = meta.client.GetConnection(ctx, ref)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			before, after := data.GetChange("attributes")
+			attributes, err := mergeAttributes(connection.GetAttributes(), before.(string), after.(string))
+			if err != nil {
+				return err
+			}
+			err = meta.retry(ctx, func() error {
+				var err error
+				// PutConnection replaces every attribute: EditConnection only merges, so removed attributes would linger
+				connection, err = meta.client.PutConnection(ctx, &apipb.Connection{
+					Ref:        ref,
+					Attributes: attributes,
+					Directed:   data.Get("directed").(bool),
+					From:       expandRef(data.Get("from")),
+					To:         expandRef(data.Get("to")),
+				})
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			return setConnectionData(data, connection)
+		},
+		Delete: func(data *schema.ResourceData, i interface{}) error {
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutDelete))
+			defer cancel()
+			ref, err := parseRefID(data.Id())
+			if err != nil {
+				return err
+			}
+			err = meta.retry(ctx, func() error {
+				return meta.client.DelConnection(ctx, ref)
+			})
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			return err
+		},
+		Importer: &schema.ResourceImporter{
+			State: func(data *schema.ResourceData, i interface{}) ([]*schema.ResourceData, error) {
+				// the connection is read by the refresh following the import, which fails if it doesn't exist
+				if _, err := parseRefID(data.Id()); err != nil {
+					return nil, err
+				}
+				// like docs, no attribute is managed until it's declared
+				return []*schema.ResourceData{data}, data.Set("attributes", "{}")
+			},
+		},
+		Timeouts:    resourceTimeouts(),
+		Description: "a connection(edge) between two docs in the graph",
+	}
+}
+
+func setConnectionData(data *schema.ResourceData, connection *apipb.Connection) error {
+	attributes, err := managedAttributes(data.Get("attributes").(string), connection.GetAttributes())
+	if err != nil {
+		return err
+	}
+	values := map[string]interface{}{
+		"gtype":      connection.GetRef().GetGtype(),
+		"gid":        connection.GetRef().GetGid(),
+		"from":       flattenRef(connection.GetFrom()),
+		"to":         flattenRef(connection.GetTo()),
+		"directed":   connection.GetDirected(),
+		"attributes": attributes,
+	}
+	for k, v := range values {
+		if err := data.Set(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// refSchema returns the schema of a block referencing a doc by its gtype & gid
+func refSchema(description string, forceNew bool) *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Required:    true,
+		ForceNew:    forceNew,
+		MaxItems:    1,
+		Description: description,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"gtype": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ForceNew:     forceNew,
+					Description:  "the type of the doc",
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+				"gid": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ForceNew:     forceNew,
+					Description:  "the unique id of the doc within its type",
+					ValidateFunc: validation.StringIsNotEmpty,
+				},
+			},
+		},
+	}
+}
+
+// expandRef returns the ref held by a block of refSchema
+func expandRef(value interface{}) *apipb.Ref {
+	values := value.([]interface{})
+	if len(values) == 0 || values[0] == nil {
+		return nil
+	}
+	ref := values[0].(map[string]interface{})
+	return &apipb.Ref{
+		Gtype: ref["gtype"].(string),
+		Gid:   ref["gid"].(string),
+	}
+}
+
+// flattenRef returns the value of a block of refSchema holding the ref
+func flattenRef(ref *apipb.Ref) []interface{} {
+	if ref == nil {
+		return nil
+	}
+	return []interface{}{map[string]interface{}{
+		"gtype": ref.GetGtype(),
+		"gid":   ref.GetGid(),
+	}}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/structpb"
+	"strings"
+	"testing"
+)
+
+const testConnectionConfig = testProviderConfig + `
+resource "graphik_doc" "alice" {
+  gtype = "user"
+  gid   = "alice"
+}
+
+resource "graphik_doc" "platform" {
+  gtype = "team"
+  gid   = "platform"
+}
+
+resource "graphik_connection" "alice_platform" {
+  gtype = "member_of"
+  from {
+    gtype = graphik_doc.alice.gtype
+    gid   = graphik_doc.alice.gid
+  }
+  to {
+    gtype = graphik_doc.platform.gtype
+    gid   = graphik_doc.platform.gid
+  }
+  attributes = jsonencode({ role = "owner" })
+}
+`
+
+func TestAccConnection(t *testing.T) {
+	client := newFakeClient()
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testProviders(client),
+		CheckDestroy: func(state *terraform.State) error {
+			if len(client.connections) != 0 || len(client.docs) != 0 {
+				return errors.Errorf("expected every doc & connection to be deleted, got: %v %v", client.docs, client.connections)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testConnectionConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("graphik_connection.alice_platform", "id", "member_of/generated-1"),
+					resource.TestCheckResourceAttr("graphik_connection.alice_platform", "from.0.gid", "alice"),
+					resource.TestCheckResourceAttr("graphik_connection.alice_platform", "to.0.gtype", "team"),
+					resource.TestCheckResourceAttr("graphik_connection.alice_platform", "directed", "true"),
+					resource.TestCheckResourceAttr("graphik_connection.alice_platform", "attributes", `{"role":"owner"}`),
+				),
+			},
+			{
+				PreConfig: func() {
+					client.connections["member_of/generated-1"].Attributes.Fields["created_at"] = structpb.NewNumberValue(1)
+				},
+				Config: strings.Replace(testConnectionConfig, `role = "owner"`, `since = 2020`, 1),
+				Check: func(state *terraform.State) error {
+					attributes, err := attributesJSON(client.connections["member_of/generated-1"].GetAttributes())
+					if err != nil {
+						return err
+					}
+					if attributes != `{"created_at":1,"since":2020}` {
+						return errors.Errorf("expected the declared attributes to be replaced & the ones added outside of terraform to be kept, got: %s", attributes)
+					}
+					return nil
+				},
+			},
+			{
+				ResourceName:      "graphik_connection.alice_platform",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// every attribute is managed after an import
+				ImportStateVerifyIgnore: []string{"attributes"},
+			},
+			{
+				// the connection is recreated when it's removed outside of terraform
+				PreConfig: func() {
+					delete(client.connections, "member_of/generated-1")
+				},
+				Config: testConnectionConfig,
+				Check:  resource.TestCheckResourceAttr("graphik_connection.alice_platform", "id", "member_of/generated-2"),
+			},
+		},
+	})
+}
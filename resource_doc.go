@@ -0,0 +1,253 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	_struct "github.com/golang/protobuf/ptypes/struct"
+	apipb "github.com/graphikDB/graphik/gen/grpc/go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"reflect"
+	"strings"
+)
+
+// resourceDoc manages a single doc, ex: a role, default settings or a tenant record every environment needs
+func resourceDoc() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"gtype": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				Description:  "the type of the doc ex: role",
+				ValidateFunc: validation.StringIsNotEmpty,
+			},
+			"gid": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "the unique id of the doc within its type. generated by graphik when unset",
+			},
+			"attributes": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "{}",
+				Description:      "JSON encoded object of the doc's attributes - use jsonencode() to build it. attributes added to the doc outside of terraform (ex: by a trigger) are ignored",
+				ValidateFunc:     validateAttributes,
+				DiffSuppressFunc: suppressEquivalentAttributes,
+			},
+		},
+		Create: func(data *schema.ResourceData, i interface{}) error {
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutCreate))
+			defer cancel()
+			attributes, err := parseAttributes(data.Get("attributes").(string))
+			if err != nil {
+				return err
+			}
+			// CreateDoc isn't retried: a retry after a lost response would create a duplicate of a doc with a generated gid
+			// & fail with AlreadyExists for a doc with a fixed gid
+			doc, err := meta.client.CreateDoc(ctx, &apipb.DocConstructor{
+				Ref: &apipb.RefConstructor{
+					Gtype: data.Get("gtype").(string),
+					Gid:   data.Get("gid").(string),
+				},
+				Attributes: attributes,
+			})
+			if err != nil {
+				return err
+			}
+			data.SetId(refID(doc.GetRef()))
+			return setDocData(data, doc)
+		},
+		Read: func(data *schema.ResourceData, i interface{}) error {
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutRead))
+			defer cancel()
+			ref, err := parseRefID(data.Id())
+			if err != nil {
+				return err
+			}
+			var doc *apipb.Doc
+			err = meta.retry(ctx, func() error {
+				var err error
+				doc, err = meta.client.GetDoc(ctx, ref)
+				return err
+			})
+			if status.Code(err) == codes.NotFound {
+				// the doc was removed outside of terraform
+				data.SetId("")
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			return setDocData(data, doc)
+		},
+		Update: func(data *schema.ResourceData, i interface{}) error {
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutUpdate))
+			defer cancel()
+			ref, err := parseRefID(data.Id())
+			if err != nil {
+				return err
+			}
+			var doc *apipb.Doc
+			err = meta.retry(ctx, func() error {
+				var err error
+				doc, err = meta.client.GetDoc(ctx, ref)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			before, after := data.GetChange("attributes")
+			attributes, err := mergeAttributes(doc.GetAttributes(), before.(string), after.(string))
+			if err != nil {
+				return err
+			}
+			err = meta.retry(ctx, func() error {
+				var err error
+				// PutDoc replaces every attribute: EditDoc only merges, so removed attributes would linger
+				doc, err = meta.client.PutDoc(ctx, &apipb.Doc{Ref: ref, Attributes: attributes})
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			return setDocData(data, doc)
+		},
+		Delete: func(data *schema.ResourceData, i interface{}) error {
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutDelete))
+			defer cancel()
+			ref, err := parseRefID(data.Id())
+			if err != nil {
+				return err
+			}
+			err = meta.retry(ctx, func() error {
+				return meta.client.DelDoc(ctx, ref)
+			})
+			if status.Code(err) == codes.NotFound {
+				return nil
+			}
+			return err
+		},
+		Importer: &schema.ResourceImporter{
+			State: func(data *schema.ResourceData, i interface{}) ([]*schema.ResourceData, error) {
+				// the doc is read by the refresh following the import, which fails if it doesn't exist
+				if _, err := parseRefID(data.Id()); err != nil {
+					return nil, err
+				}
+				// no attribute is managed until it's declared, so the ones maintained by triggers (ex: created_at) survive the next apply
+				return []*schema.ResourceData{data}, data.Set("attributes", "{}")
+			},
+		},
+		Timeouts:    resourceTimeouts(),
+		Description: "a doc(node) in the graph. graphik doesn't expose doc metadata, so there are no created_at/updated_at/version attributes",
+	}
+}
+
+func setDocData(data *schema.ResourceData, doc *apipb.Doc) error {
+	attributes, err := managedAttributes(data.Get("attributes").(string), doc.GetAttributes())
+	if err != nil {
+		return err
+	}
+	if err := data.Set("gtype", doc.GetRef().GetGtype()); err != nil {
+		return err
+	}
+	if err := data.Set("gid", doc.GetRef().GetGid()); err != nil {
+		return err
+	}
+	return data.Set("attributes", attributes)
+}
+
+// parseRefID parses the terraform id of a doc/connection: <gtype>/<gid>
+func parseRefID(id string) (*apipb.Ref, error) {
+	parts := strings.SplitN(id, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, errors.Errorf("unexpected id %q: expected <gtype>/<gid>", id)
+	}
+	return &apipb.Ref{Gtype: parts[0], Gid: parts[1]}, nil
+}
+
+// parseAttributes decodes JSON encoded attributes of a doc/connection
+func parseAttributes(value string) (*_struct.Struct, error) {
+	attributes := map[string]interface{}{}
+	if value != "" {
+		if err := json.Unmarshal([]byte(value), &attributes); err != nil {
+			return nil, errors.Wrap(err, "attributes must be a JSON encoded object")
+		}
+	}
+	return structpb.NewStruct(attributes)
+}
+
+// validateAttributes is a ValidateFunc that rejects attributes that aren't a JSON encoded object
+func validateAttributes(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{errors.Errorf("expected type of %s to be string", k)}
+	}
+	if _, err := parseAttributes(v); err != nil {
+		return nil, []error{errors.Wrap(err, k)}
+	}
+	return nil, nil
+}
+
+// suppressEquivalentAttributes suppresses diffs between JSON encoded attributes that only differ in formatting or key order
+func suppressEquivalentAttributes(k, old, new string, d *schema.ResourceData) bool {
+	oldAttributes, err := parseAttributes(old)
+	if err != nil {
+		return false
+	}
+	newAttributes, err := parseAttributes(new)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(oldAttributes.AsMap(), newAttributes.AsMap())
+}
+
+// managedAttributes returns the JSON encoded attributes held by the server that terraform manages: the declared ones.
+// Attributes added outside of terraform (ex: by a trigger) are left out so they don't cause a diff, even when no attributes
+// are declared.
+func managedAttributes(declared string, attributes *_struct.Struct) (string, error) {
+	known, err := parseAttributes(declared)
+	if err != nil {
+		return "", err
+	}
+	managed := &_struct.Struct{Fields: map[string]*_struct.Value{}}
+	for k, v := range attributes.GetFields() {
+		if _, ok := known.GetFields()[k]; ok {
+			managed.Fields[k] = v
+		}
+	}
+	return attributesJSON(managed)
+}
+
+// mergeAttributes returns the attributes held by the server with the previously managed attributes replaced by the
+// declared ones, so the attributes added outside of terraform (ex: by a trigger) survive a PutDoc/PutConnection
+func mergeAttributes(attributes *_struct.Struct, before, after string) (*_struct.Struct, error) {
+	previous, err := parseAttributes(before)
+	if err != nil {
+		return nil, err
+	}
+	declared, err := parseAttributes(after)
+	if err != nil {
+		return nil, err
+	}
+	merged := &_struct.Struct{Fields: map[string]*_struct.Value{}}
+	for k, v := range attributes.GetFields() {
+		if _, ok := previous.GetFields()[k]; !ok {
+			merged.Fields[k] = v
+		}
+	}
+	for k, v := range declared.GetFields() {
+		merged.Fields[k] = v
+	}
+	return merged, nil
+}
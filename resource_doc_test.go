@@ -0,0 +1,141 @@
+package main
+
+import (
+	apipb "github.com/graphikDB/graphik/gen/grpc/go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/structpb"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const testDocConfig = testProviderConfig + `
+resource "graphik_doc" "admin" {
+  gtype      = "role"
+  gid        = "admin"
+  attributes = jsonencode({ permissions = ["read", "write"], builtin = true })
+}
+
+resource "graphik_doc" "generated" {
+  gtype = "setting"
+}
+`
+
+func TestAccDoc(t *testing.T) {
+	client := newFakeClient()
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testProviders(client),
+		CheckDestroy: func(state *terraform.State) error {
+			if len(client.docs) != 0 {
+				return errors.Errorf("expected every doc to be deleted, got: %v", client.docs)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testDocConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("graphik_doc.admin", "id", "role/admin"),
+					resource.TestCheckResourceAttr("graphik_doc.admin", "attributes", `{"builtin":true,"permissions":["read","write"]}`),
+					resource.TestCheckResourceAttr("graphik_doc.generated", "id", "setting/generated-1"),
+					resource.TestCheckResourceAttr("graphik_doc.generated", "gid", "generated-1"),
+					resource.TestCheckResourceAttr("graphik_doc.generated", "attributes", "{}"),
+				),
+			},
+			{
+				// attributes added outside of terraform, ex: by a trigger, don't cause a diff, even when none are declared
+				PreConfig: func() {
+					client.docs["role/admin"].Attributes.Fields["updated_at"] = structpb.NewNumberValue(1)
+					client.docs["setting/generated-1"].Attributes = &structpb.Struct{Fields: map[string]*structpb.Value{"created_at": structpb.NewNumberValue(1)}}
+				},
+				Config:   testDocConfig,
+				PlanOnly: true,
+			},
+			{
+				Config: strings.Replace(testDocConfig, `permissions = ["read", "write"], builtin = true`, `permissions = ["read"]`, 1),
+				Check: func(state *terraform.State) error {
+					attributes, err := attributesJSON(client.docs["role/admin"].GetAttributes())
+					if err != nil {
+						return err
+					}
+					if attributes != `{"permissions":["read"],"updated_at":1}` {
+						return errors.Errorf("expected the removed attributes to be removed & the ones added outside of terraform to be kept, got: %s", attributes)
+					}
+					return nil
+				},
+			},
+			{
+				ResourceName:      "graphik_doc.admin",
+				ImportState:       true,
+				ImportStateVerify: true,
+				// no attribute is managed after an import until it's declared
+				ImportStateVerifyIgnore: []string{"attributes"},
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if attributes := states[0].Attributes["attributes"]; attributes != "{}" {
+						return errors.Errorf("expected no attribute to be managed, got: %s", attributes)
+					}
+					return nil
+				},
+			},
+			{
+				ResourceName:  "graphik_doc.admin",
+				ImportState:   true,
+				ImportStateId: "role",
+				ExpectError:   regexp.MustCompile("expected <gtype>/<gid>"),
+			},
+			{
+				ResourceName:  "graphik_doc.admin",
+				ImportState:   true,
+				ImportStateId: "role/missing",
+				ExpectError:   regexp.MustCompile("non-existent"),
+			},
+		},
+	})
+}
+
+func TestDocImportApply(t *testing.T) {
+	client := newFakeClient()
+	attributes, err := structpb.NewStruct(map[string]interface{}{"created_at": 123, "name": "root"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.docs["role/admin"] = &apipb.Doc{Ref: &apipb.Ref{Gtype: "role", Gid: "admin"}, Attributes: attributes}
+	meta := newProviderMeta(client)
+	r := resourceDoc()
+	imported, err := r.Importer.State(r.Data(&terraform.InstanceState{ID: "role/admin"}), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := r.Refresh(imported[0].State(), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Attributes["attributes"] != "{}" {
+		t.Fatalf("expected no attribute to be managed after the import, got: %s", state.Attributes["attributes"])
+	}
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"gtype":      "role",
+		"gid":        "admin",
+		"attributes": `{"name":"admin"}`,
+	})
+	diff, err := r.Diff(state, config, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff.RequiresNew() || diff.Attributes["attributes"] == nil {
+		t.Fatalf("expected the declared attributes to be updated in place, got: %v", diff)
+	}
+	state, err = r.Apply(state, diff, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Attributes["attributes"] != `{"name":"admin"}` {
+		t.Errorf("expected only the declared attributes to be managed, got: %s", state.Attributes["attributes"])
+	}
+	// the attribute maintained by a trigger is kept
+	if applied, _ := attributesJSON(client.docs["role/admin"].GetAttributes()); applied != `{"created_at":123,"name":"admin"}` {
+		t.Errorf("expected the attributes added outside of terraform to be kept, got: %s", applied)
+	}
+}
@@ -0,0 +1,524 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	apipb "github.com/graphikDB/graphik/gen/grpc/go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// defaultDocsChunkSize is the number of docs written or deleted per batch call when chunk_size isn't set
+const defaultDocsChunkSize = 500
+
+// seedDoc is a single doc declared by graphik_docs, inline or in a source file
+type seedDoc struct {
+	Gtype      string                 `json:"gtype"`
+	Gid        string                 `json:"gid"`
+	Attributes map[string]interface{} `json:"attributes"`
+}
+
+// docsGetter is implemented by both schema.ResourceData & schema.ResourceDiff
+type docsGetter interface {
+	Get(key string) interface{}
+	HasChange(key string) bool
+}
+
+// resourceDocs seeds many docs at once through the batch rpcs. The docs themselves aren't stored in state:
+// only a content hash & a hash per managed doc, keyed by <gtype>/<gid>.
+func resourceDocs() *schema.Resource {
+	write := func(data *schema.ResourceData, i interface{}, timeout string) error {
+		meta := i.(*providerMeta)
+		ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, timeout))
+		defer cancel()
+		if data.Id() != "" && !data.HasChange("content_hash") && !data.HasChange("refs") {
+			// ex: only chunk_size changed
+			return nil
+		}
+		docs, err := loadSeedDocs(data)
+		if err != nil {
+			return err
+		}
+		before, _ := data.GetChange("refs")
+		applied := map[string]interface{}{}
+		for k, v := range before.(map[string]interface{}) {
+			applied[k] = v
+		}
+		created := data.Id() == ""
+		if created {
+			data.SetId(resource.UniqueId())
+		}
+		if err := writeSeedDocs(ctx, meta, data.Get("chunk_size").(int), docs, applied); err != nil {
+			if created {
+				if len(applied) == 0 {
+					data.SetId("")
+					return err
+				}
+				// terraform taints a resource whose create failed & replaces it on the next apply, so the whole
+				// configuration is kept in state for the destroy to delete the docs that were written
+				if err := data.Set("refs", applied); err != nil {
+					return err
+				}
+				return err
+			}
+			// only record the docs that were written so the next plan picks up where this apply stopped
+			data.Partial(true)
+			if err := data.Set("refs", applied); err != nil {
+				return err
+			}
+			data.SetPartial("refs")
+			return err
+		}
+		hash, err := seedDocsHash(docs)
+		if err != nil {
+			return err
+		}
+		if err := data.Set("content_hash", hash); err != nil {
+			return err
+		}
+		return data.Set("refs", applied)
+	}
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"docs": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"docs", "source"},
+				Description:  "JSON encoded list of docs, each an object with a gtype, gid & attributes - use jsonencode() to build it. only a hash is stored in state",
+				ValidateFunc: func(i interface{}, k string) ([]string, []error) {
+					if _, err := parseSeedDocs(i.(string)); err != nil {
+						return nil, []error{errors.Wrap(err, k)}
+					}
+					return nil, nil
+				},
+				StateFunc: func(i interface{}) string {
+					sum := sha256.Sum256([]byte(i.(string)))
+					return hex.EncodeToString(sum[:])
+				},
+			},
+			"source": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"docs", "source"},
+				Description:  "path to a .jsonl file holding a doc object per line, or a .csv file with gtype & gid columns whose other columns are string attributes",
+			},
+			"chunk_size": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultDocsChunkSize,
+				Description:  "the number of docs written or deleted per batch call",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"content_hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "hash of every declared doc",
+			},
+			"refs": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "hash of every managed doc keyed by <gtype>/<gid>",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"added": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "the number of docs added by the pending change",
+			},
+			"changed": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "the number of docs changed by the pending change",
+			},
+			"removed": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "the number of docs removed by the pending change",
+			},
+		},
+		Create: func(data *schema.ResourceData, i interface{}) error {
+			return write(data, i, schema.TimeoutCreate)
+		},
+		Read: func(data *schema.ResourceData, i interface{}) error {
+			// the docs aren't read back: a refresh would fetch every seeded doc
+			for _, k := range []string{"added", "changed", "removed"} {
+				if err := data.Set(k, 0); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Update: func(data *schema.ResourceData, i interface{}) error {
+			return write(data, i, schema.TimeoutUpdate)
+		},
+		Delete: func(data *schema.ResourceData, i interface{}) error {
+			meta := i.(*providerMeta)
+			ctx, cancel := context.WithTimeout(context.Background(), meta.timeout(data, schema.TimeoutDelete))
+			defer cancel()
+			applied := data.Get("refs").(map[string]interface{})
+			err := writeSeedDocs(ctx, meta, data.Get("chunk_size").(int), nil, applied)
+			if err != nil {
+				if err := data.Set("refs", applied); err != nil {
+					return err
+				}
+			}
+			return err
+		},
+		CustomizeDiff: func(diff *schema.ResourceDiff, i interface{}) error {
+			if !diff.NewValueKnown("docs") || !diff.NewValueKnown("source") {
+				for _, k := range []string{"content_hash", "refs", "added", "changed", "removed"} {
+					if err := diff.SetNewComputed(k); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			if !diff.HasChange("docs") && diff.Get("source").(string) == "" {
+				// unchanged inline docs: only their hash is available
+				return nil
+			}
+			docs, err := loadSeedDocs(diff)
+			if err != nil {
+				return err
+			}
+			hash, err := seedDocsHash(docs)
+			if err != nil {
+				return err
+			}
+			if diff.Id() != "" && diff.Get("content_hash").(string) == hash {
+				return nil
+			}
+			before, _ := diff.GetChange("refs")
+			after, err := seedDocRefs(docs)
+			if err != nil {
+				return err
+			}
+			added, changed, removed := 0, 0, 0
+			for ref, v := range after {
+				previous, ok := before.(map[string]interface{})[ref]
+				if !ok {
+					added++
+				} else if previous != v {
+					changed++
+				}
+			}
+			for ref := range before.(map[string]interface{}) {
+				if _, ok := after[ref]; !ok {
+					removed++
+				}
+			}
+			values := map[string]interface{}{
+				"content_hash": hash,
+				"refs":         after,
+				"added":        added,
+				"changed":      changed,
+				"removed":      removed,
+			}
+			for k, v := range values {
+				if err := diff.SetNew(k, v); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Timeouts:    resourceTimeouts(),
+		Description: "seeds many docs at once through the batch rpcs without storing them in state. docs changed outside of terraform aren't detected",
+	}
+}
+
+// loadSeedDocs returns the docs declared inline or in the source file
+func loadSeedDocs(data docsGetter) ([]*seedDoc, error) {
+	var docs []*seedDoc
+	var err error
+	if source := data.Get("source").(string); source != "" {
+		docs, err = readSeedDocs(source)
+	} else if data.HasChange("docs") {
+		docs, err = parseSeedDocs(data.Get("docs").(string))
+	} else {
+		return nil, errors.New("docs are unchanged: only their hash is in state")
+	}
+	if err != nil {
+		return nil, err
+	}
+	refs := map[string]struct{}{}
+	for _, doc := range docs {
+		ref := refID(&apipb.Ref{Gtype: doc.Gtype, Gid: doc.Gid})
+		if _, ok := refs[ref]; ok {
+			return nil, errors.Errorf("doc %s is declared more than once", ref)
+		}
+		refs[ref] = struct{}{}
+	}
+	return docs, nil
+}
+
+// parseSeedDocs decodes a JSON encoded list of docs
+func parseSeedDocs(value string) ([]*seedDoc, error) {
+	var docs []*seedDoc
+	if err := json.Unmarshal([]byte(value), &docs); err != nil {
+		return nil, errors.Wrap(err, "docs must be a JSON encoded list of objects")
+	}
+	for i, doc := range docs {
+		if err := checkSeedDoc(doc); err != nil {
+			return nil, errors.Wrapf(err, "doc %v", i)
+		}
+	}
+	return docs, nil
+}
+
+// readSeedDocs reads the docs held by a .jsonl or .csv file
+func readSeedDocs(path string) ([]*seedDoc, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open source")
+	}
+	defer f.Close()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".jsonl":
+		return readSeedDocsJSONL(f, path)
+	case ".csv":
+		return readSeedDocsCSV(f, path)
+	default:
+		return nil, errors.Errorf("unsupported source %s: expected a .jsonl or .csv file", path)
+	}
+}
+
+func readSeedDocsJSONL(r io.Reader, path string) ([]*seedDoc, error) {
+	var docs []*seedDoc
+	decoder := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		doc := &seedDoc{}
+		if err := decoder.Decode(doc); err == io.EOF {
+			return docs, nil
+		} else if err != nil {
+			return nil, errors.Wrapf(err, "%s: doc %v", path, line)
+		}
+		if err := checkSeedDoc(doc); err != nil {
+			return nil, errors.Wrapf(err, "%s: doc %v", path, line)
+		}
+		docs = append(docs, doc)
+	}
+}
+
+func readSeedDocsCSV(r io.Reader, path string) ([]*seedDoc, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, errors.Wrap(err, path)
+	}
+	if len(records) == 0 {
+		return nil, errors.Errorf("%s: missing header", path)
+	}
+	header := records[0]
+	columns := map[string]int{}
+	for i, column := range header {
+		columns[column] = i
+	}
+	for _, column := range []string{"gtype", "gid"} {
+		if _, ok := columns[column]; !ok {
+			return nil, errors.Errorf("%s: missing %s column", path, column)
+		}
+	}
+	var docs []*seedDoc
+	for line, record := range records[1:] {
+		doc := &seedDoc{Attributes: map[string]interface{}{}}
+		for i, value := range record {
+			switch header[i] {
+			case "gtype":
+				doc.Gtype = value
+			case "gid":
+				doc.Gid = value
+			default:
+				doc.Attributes[header[i]] = value
+			}
+		}
+		if err := checkSeedDoc(doc); err != nil {
+			return nil, errors.Wrapf(err, "%s: line %v", path, line+2)
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+func checkSeedDoc(doc *seedDoc) error {
+	if doc == nil {
+		return errors.New("expected an object")
+	}
+	if doc.Gtype == "" {
+		return errors.New("missing gtype")
+	}
+	if doc.Gid == "" {
+		// docs are tracked by ref, so graphik can't be left to generate the gid
+		return errors.New("missing gid")
+	}
+	return nil
+}
+
+// seedDocHash returns the hash of a single doc recorded in refs
+func seedDocHash(doc *seedDoc) (string, error) {
+	attributes, err := json.Marshal(doc.Attributes)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(attributes)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// seedDocRefs returns the hash of every doc keyed by <gtype>/<gid>
+func seedDocRefs(docs []*seedDoc) (map[string]interface{}, error) {
+	refs := map[string]interface{}{}
+	for _, doc := range docs {
+		hash, err := seedDocHash(doc)
+		if err != nil {
+			return nil, err
+		}
+		refs[refID(&apipb.Ref{Gtype: doc.Gtype, Gid: doc.Gid})] = hash
+	}
+	return refs, nil
+}
+
+// seedDocsHash returns the content hash of every doc, independent of their order
+func seedDocsHash(docs []*seedDoc) (string, error) {
+	refs, err := seedDocRefs(docs)
+	if err != nil {
+		return "", err
+	}
+	keys := make([]string, 0, len(refs))
+	for ref := range refs {
+		keys = append(keys, ref)
+	}
+	sort.Strings(keys)
+	digest := sha256.New()
+	for _, ref := range keys {
+		fmt.Fprintf(digest, "%s=%s\n", ref, refs[ref])
+	}
+	return hex.EncodeToString(digest.Sum(nil)), nil
+}
+
+// writeSeedDocs creates the docs missing from applied, puts the changed ones & deletes the managed docs that are no
+// longer declared, chunkSize docs per batch call. applied holds the hash of every managed doc & is updated after every call.
+func writeSeedDocs(ctx context.Context, meta *providerMeta, chunkSize int, docs []*seedDoc, applied map[string]interface{}) error {
+	if chunkSize <= 0 {
+		// ex: state saved without chunk_size
+		chunkSize = defaultDocsChunkSize
+	}
+	declared := map[string]struct{}{}
+	var added, changed []*seedDoc
+	for _, doc := range docs {
+		ref := refID(&apipb.Ref{Gtype: doc.Gtype, Gid: doc.Gid})
+		declared[ref] = struct{}{}
+		hash, err := seedDocHash(doc)
+		if err != nil {
+			return err
+		}
+		previous, ok := applied[ref]
+		switch {
+		case !ok:
+			added = append(added, doc)
+		case previous != hash:
+			changed = append(changed, doc)
+		}
+	}
+	removed := map[string][]string{}
+	for ref := range applied {
+		if _, ok := declared[ref]; !ok {
+			parsed, err := parseRefID(ref)
+			if err != nil {
+				return err
+			}
+			removed[parsed.GetGtype()] = append(removed[parsed.GetGtype()], parsed.GetGid())
+		}
+	}
+	// PutDocs creates the added docs too: unlike CreateDocs, it's idempotent so a retried chunk that was already
+	// written doesn't fail with AlreadyExists
+	written := append(added, changed...)
+	for start := 0; start < len(written); start += chunkSize {
+		chunk := written[start:minInt(start+chunkSize, len(written))]
+		put := &apipb.Docs{}
+		for _, doc := range chunk {
+			attributes, err := structpb.NewStruct(doc.Attributes)
+			if err != nil {
+				return err
+			}
+			put.Docs = append(put.Docs, &apipb.Doc{
+				Ref:        &apipb.Ref{Gtype: doc.Gtype, Gid: doc.Gid},
+				Attributes: attributes,
+			})
+		}
+		if err := meta.retry(ctx, func() error {
+			_, err := meta.client.PutDocs(ctx, put)
+			return err
+		}); err != nil {
+			return errors.Wrap(err, "failed to put docs")
+		}
+		if err := recordSeedDocs(chunk, applied); err != nil {
+			return err
+		}
+	}
+	gtypes := make([]string, 0, len(removed))
+	for gtype := range removed {
+		gtypes = append(gtypes, gtype)
+	}
+	sort.Strings(gtypes)
+	for _, gtype := range gtypes {
+		gids := removed[gtype]
+		sort.Strings(gids)
+		for start := 0; start < len(gids); start += chunkSize {
+			chunk := gids[start:minInt(start+chunkSize, len(gids))]
+			if err := meta.retry(ctx, func() error {
+				return meta.client.DelDocs(ctx, gidFilter(gtype, chunk))
+			}); err != nil && !docsNotFound(err) {
+				return errors.Wrap(err, "failed to delete docs")
+			}
+			for _, gid := range chunk {
+				delete(applied, refID(&apipb.Ref{Gtype: gtype, Gid: gid}))
+			}
+		}
+	}
+	return nil
+}
+
+// recordSeedDocs records the hash of the written docs in applied
+func recordSeedDocs(docs []*seedDoc, applied map[string]interface{}) error {
+	for _, doc := range docs {
+		hash, err := seedDocHash(doc)
+		if err != nil {
+			return err
+		}
+		applied[refID(&apipb.Ref{Gtype: doc.Gtype, Gid: doc.Gid})] = hash
+	}
+	return nil
+}
+
+// gidFilter returns the filter matching the docs of the given type with one of the gids
+func gidFilter(gtype string, gids []string) *apipb.Filter {
+	quoted := make([]string, 0, len(gids))
+	for _, gid := range gids {
+		quoted = append(quoted, strconv.Quote(gid))
+	}
+	return &apipb.Filter{
+		Gtype:      gtype,
+		Expression: fmt.Sprintf("this.ref.gid in [%s]", strings.Join(quoted, ", ")),
+		Limit:      uint64(len(gids)),
+	}
+}
+
+// docsNotFound reports whether a DelDocs error means none of the docs exist anymore. graphik returns NotFound
+// when the type has no docs, but an Unknown "not found" error when the filter matches none of its docs.
+func docsNotFound(err error) bool {
+	s, _ := status.FromError(err)
+	return s.Code() == codes.NotFound || (s.Code() == codes.Unknown && s.Message() == "not found")
+}
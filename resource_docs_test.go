@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	apipb "github.com/graphikDB/graphik/gen/grpc/go"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func testDocsConfig(docs string) string {
+	return testProviderConfig + fmt.Sprintf(`
+resource "graphik_docs" "seed" {
+  chunk_size = 2
+  docs       = jsonencode(%s)
+}
+`, docs)
+}
+
+func TestAccDocs(t *testing.T) {
+	client := newFakeClient()
+	source := filepath.Join(t.TempDir(), "docs.jsonl")
+	sourceConfig := testProviderConfig + fmt.Sprintf(`
+resource "graphik_docs" "seed" {
+  source = %q
+}
+`, source)
+	checkDocs := func(expected ...string) resource.TestCheckFunc {
+		return func(state *terraform.State) error {
+			var docs []string
+			for ref, doc := range client.docs {
+				attributes, err := attributesJSON(doc.GetAttributes())
+				if err != nil {
+					return err
+				}
+				docs = append(docs, ref+"="+attributes)
+			}
+			sort.Strings(docs)
+			if !reflect.DeepEqual(docs, expected) {
+				return errors.Errorf("expected docs %v, got: %v", expected, docs)
+			}
+			return nil
+		}
+	}
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testProviders(client),
+		CheckDestroy: func(state *terraform.State) error {
+			if len(client.docs) != 0 {
+				return errors.Errorf("expected every doc to be deleted, got: %v", client.docs)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testDocsConfig(`[
+    { gtype = "role", gid = "admin", attributes = { level = 1 } },
+    { gtype = "role", gid = "viewer", attributes = { level = 2 } },
+    { gtype = "setting", gid = "defaults", attributes = { theme = "dark" } },
+  ]`),
+				Check: resource.ComposeTestCheckFunc(
+					checkDocs(`role/admin={"level":1}`, `role/viewer={"level":2}`, `setting/defaults={"theme":"dark"}`),
+					resource.TestCheckResourceAttr("graphik_docs.seed", "added", "3"),
+					resource.TestCheckResourceAttr("graphik_docs.seed", "refs.%", "3"),
+					resource.TestMatchResourceAttr("graphik_docs.seed", "docs", regexp.MustCompile("^[0-9a-f]{64}$")),
+					func(state *terraform.State) error {
+						if client.batchCalls != 2 {
+							return errors.Errorf("expected 2 batch calls of 2 docs, got: %v", client.batchCalls)
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: testDocsConfig(`[
+    { gtype = "role", gid = "admin", attributes = { level = 0 } },
+    { gtype = "setting", gid = "defaults", attributes = { theme = "dark" } },
+    { gtype = "setting", gid = "limits", attributes = { max = 10 } },
+  ]`),
+				Check: resource.ComposeTestCheckFunc(
+					checkDocs(`role/admin={"level":0}`, `setting/defaults={"theme":"dark"}`, `setting/limits={"max":10}`),
+					resource.TestCheckResourceAttr("graphik_docs.seed", "added", "1"),
+					resource.TestCheckResourceAttr("graphik_docs.seed", "changed", "1"),
+					resource.TestCheckResourceAttr("graphik_docs.seed", "removed", "1"),
+				),
+			},
+			{
+				// the docs move to a source file without changing
+				PreConfig: func() {
+					lines := `{"gtype": "role", "gid": "admin", "attributes": {"level": 0}}
+{"gtype": "setting", "gid": "defaults", "attributes": {"theme": "dark"}}
+{"gtype": "setting", "gid": "limits", "attributes": {"max": 10}}
+`
+					if err := ioutil.WriteFile(source, []byte(lines), 0600); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: sourceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					checkDocs(`role/admin={"level":0}`, `setting/defaults={"theme":"dark"}`, `setting/limits={"max":10}`),
+					resource.TestCheckResourceAttr("graphik_docs.seed", "added", "0"),
+					resource.TestCheckResourceAttr("graphik_docs.seed", "refs.%", "3"),
+				),
+			},
+			{
+				// changing the content of the source file is a change
+				PreConfig: func() {
+					lines := `{"gtype": "setting", "gid": "defaults", "attributes": {"theme": "light"}}
+`
+					if err := ioutil.WriteFile(source, []byte(lines), 0600); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: sourceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					checkDocs(`setting/defaults={"theme":"light"}`),
+					resource.TestCheckResourceAttr("graphik_docs.seed", "changed", "1"),
+					resource.TestCheckResourceAttr("graphik_docs.seed", "removed", "2"),
+				),
+			},
+			{
+				Config:      testDocsConfig(`[{ gtype = "role", gid = "admin" }, { gtype = "role", gid = "admin" }]`),
+				ExpectError: regexp.MustCompile("doc role/admin is declared more than once"),
+			},
+		},
+	})
+}
+
+func TestWriteSeedDocs(t *testing.T) {
+	client := newFakeClient()
+	client.batchErrs = []error{nil, status.Error(codes.PermissionDenied, "denied")}
+	meta := newProviderMeta(client)
+	docs := []*seedDoc{{Gtype: "user", Gid: "a"}, {Gtype: "user", Gid: "b"}, {Gtype: "user", Gid: "c"}}
+	applied := map[string]interface{}{}
+	if err := writeSeedDocs(context.Background(), meta, 2, docs, applied); err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Fatalf("expected the second chunk to be denied, got: %v", err)
+	}
+	if len(applied) != 2 || applied["user/a"] == nil || applied["user/b"] == nil {
+		t.Fatalf("expected only the first chunk to be applied, got: %v", applied)
+	}
+	if err := writeSeedDocs(context.Background(), meta, 2, docs[1:], applied); err != nil {
+		t.Fatal(err)
+	}
+	var refs []string
+	for ref := range client.docs {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	if !reflect.DeepEqual(refs, []string{"user/b", "user/c"}) || len(applied) != 2 {
+		t.Fatalf("expected user/a to be removed & user/c to be added, got: %v %v", refs, applied)
+	}
+	// a doc written by a chunk whose response was lost is written again instead of failing with AlreadyExists
+	client.docs["user/d"] = &apipb.Doc{Ref: &apipb.Ref{Gtype: "user", Gid: "d"}}
+	if err := writeSeedDocs(context.Background(), meta, 2, append(docs[1:], &seedDoc{Gtype: "user", Gid: "d"}), applied); err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 3 || applied["user/d"] == nil {
+		t.Fatalf("expected user/d to be applied, got: %v", applied)
+	}
+	// a state without chunk_size falls back to the default chunk size
+	if err := writeSeedDocs(context.Background(), meta, 0, nil, applied); err != nil {
+		t.Fatal(err)
+	}
+	if len(client.docs) != 0 || len(applied) != 0 {
+		t.Fatalf("expected every doc to be deleted, got: %v %v", client.docs, applied)
+	}
+}
+
+func TestReadSeedDocs(t *testing.T) {
+	dir := t.TempDir()
+	for _, tc := range []struct {
+		name    string
+		content string
+		docs    []*seedDoc
+		err     string
+	}{
+		{
+			name:    "docs.csv",
+			content: "gtype,gid,name\nuser,alice,Alice\nuser,bob,\"Bob, Jr.\"\n",
+			docs: []*seedDoc{
+				{Gtype: "user", Gid: "alice", Attributes: map[string]interface{}{"name": "Alice"}},
+				{Gtype: "user", Gid: "bob", Attributes: map[string]interface{}{"name": "Bob, Jr."}},
+			},
+		},
+		{
+			name:    "docs.jsonl",
+			content: "{\"gtype\": \"user\", \"gid\": \"alice\", \"attributes\": {\"age\": 30}}\n\n{\"gtype\": \"user\", \"gid\": \"bob\"}\n",
+			docs: []*seedDoc{
+				{Gtype: "user", Gid: "alice", Attributes: map[string]interface{}{"age": float64(30)}},
+				{Gtype: "user", Gid: "bob"},
+			},
+		},
+		{name: "missing-gid.csv", content: "gtype,name\nuser,Alice\n", err: "missing gid column"},
+		{name: "missing-gid.jsonl", content: "{\"gtype\": \"user\"}\n", err: "doc 1: missing gid"},
+		{name: "docs.json", content: "[]", err: "expected a .jsonl or .csv file"},
+	} {
+		path := filepath.Join(dir, tc.name)
+		if err := ioutil.WriteFile(path, []byte(tc.content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		docs, err := readSeedDocs(path)
+		switch {
+		case tc.err == "" && err != nil:
+			t.Errorf("%s: unexpected error: %s", tc.name, err)
+		case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+			t.Errorf("%s: expected error containing %q, got: %v", tc.name, tc.err, err)
+		case tc.err == "" && !reflect.DeepEqual(docs, tc.docs):
+			t.Errorf("%s: unexpected docs: %v", tc.name, docs)
+		}
+	}
+	filter := gidFilter("user", []string{"alice", `b"ob`})
+	if filter.GetExpression() != `this.ref.gid in ["alice", "b\"ob"]` || filter.GetLimit() != 2 {
+		t.Fatalf("unexpected filter: %v", filter)
+	}
+}
+
+func TestAccDocs_failedCreate(t *testing.T) {
+	client := newFakeClient()
+	config := testDocsConfig(`[
+    { gtype = "user", gid = "a" },
+    { gtype = "user", gid = "b" },
+    { gtype = "user", gid = "c" },
+  ]`)
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testProviders(client),
+		CheckDestroy: func(state *terraform.State) error {
+			if len(client.docs) != 0 {
+				return errors.Errorf("expected every doc to be deleted, got: %v", client.docs)
+			}
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				// the second chunk fails after the first one was written
+				PreConfig: func() {
+					client.batchErrs = []error{nil, status.Error(codes.PermissionDenied, "denied")}
+				},
+				Config:      config,
+				ExpectError: regexp.MustCompile("denied"),
+			},
+			{
+				// the tainted resource is replaced: the written docs are deleted & every doc is written again
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("graphik_docs.seed", "refs.%", "3"),
+					resource.TestCheckResourceAttr("graphik_docs.seed", "chunk_size", "2"),
+					func(state *terraform.State) error {
+						if len(client.docs) != 3 {
+							return errors.Errorf("expected every doc to be written, got: %v", client.docs)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
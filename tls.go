@@ -0,0 +1,100 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/credentials"
+	"io/ioutil"
+)
+
+// tlsSchema returns the provider arguments configuring transport security of the connection to graphik
+func tlsSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"ca_cert_file": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			Description:   "path to a PEM encoded CA certificate used to verify graphik's server certificate instead of the system roots",
+			ConflictsWith: []string{"ca_cert_pem"},
+		},
+		"ca_cert_pem": {
+			Type:          schema.TypeString,
+			Optional:      true,
+			Description:   "PEM encoded CA certificate used to verify graphik's server certificate instead of the system roots",
+			ConflictsWith: []string{"ca_cert_file"},
+		},
+		"client_cert": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "PEM encoded client certificate presented to graphik for mutual TLS (use file() to read it from disk)",
+			RequiredWith: []string{"client_key"},
+		},
+		"client_key": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Sensitive:    true,
+			Description:  "PEM encoded private key of client_cert (use file() to read it from disk)",
+			RequiredWith: []string{"client_cert"},
+		},
+		"tls_server_name": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "server name used to verify graphik's server certificate when it differs from host",
+		},
+		"insecure": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "connect to graphik without TLS. when unset, TLS is used only if one of the other TLS arguments is set",
+		},
+	}
+}
+
+// tlsArguments are the provider arguments that enable TLS when insecure is unset
+var tlsArguments = []string{"ca_cert_file", "ca_cert_pem", "client_cert", "client_key", "tls_server_name"}
+
+// transportCredentials returns the transport credentials of the connection to graphik, or nil if the connection is insecure
+func transportCredentials(data *schema.ResourceData) (credentials.TransportCredentials, error) {
+	useTLS := false
+	for _, k := range tlsArguments {
+		if data.Get(k).(string) != "" {
+			useTLS = true
+		}
+	}
+	// GetOkExists tells an explicit insecure = false (TLS with the system roots) apart from an unset argument
+	if insecure, ok := data.GetOkExists("insecure"); ok {
+		if insecure.(bool) && useTLS {
+			return nil, errors.New("insecure can't be combined with ca_cert_file, ca_cert_pem, client_cert, client_key or tls_server_name")
+		}
+		useTLS = !insecure.(bool)
+	}
+	if !useTLS {
+		return nil, nil
+	}
+	config := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: data.Get("tls_server_name").(string),
+	}
+	caCert := []byte(data.Get("ca_cert_pem").(string))
+	if path := data.Get("ca_cert_file").(string); path != "" {
+		var err error
+		caCert, err = ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read ca_cert_file")
+		}
+	}
+	if len(caCert) > 0 {
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, errors.New("failed to parse CA certificate: no PEM encoded certificates found")
+		}
+	}
+	if clientCert := data.Get("client_cert").(string); clientCert != "" {
+		cert, err := tls.X509KeyPair([]byte(clientCert), []byte(data.Get("client_key").(string)))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse client_cert/client_key")
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(config), nil
+}
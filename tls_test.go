@@ -0,0 +1,62 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTransportCredentials(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "graphik"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	privateKey := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	providerSchema := Provider().(*schema.Provider).Schema
+	for _, tc := range []struct {
+		raw map[string]interface{}
+		tls bool
+		err string
+	}{
+		{raw: map[string]interface{}{}},
+		{raw: map[string]interface{}{"insecure": true}},
+		{raw: map[string]interface{}{"insecure": false}, tls: true},
+		{raw: map[string]interface{}{"ca_cert_pem": cert, "tls_server_name": "graphik"}, tls: true},
+		{raw: map[string]interface{}{"ca_cert_pem": cert, "client_cert": cert, "client_key": privateKey}, tls: true},
+		{raw: map[string]interface{}{"ca_cert_pem": "not a certificate"}, err: "no PEM encoded certificates found"},
+		{raw: map[string]interface{}{"client_cert": cert, "client_key": "not a key"}, err: "failed to parse client_cert/client_key"},
+		{raw: map[string]interface{}{"insecure": true, "ca_cert_pem": cert}, err: "insecure can't be combined"},
+	} {
+		creds, err := transportCredentials(schema.TestResourceDataRaw(t, providerSchema, tc.raw))
+		switch {
+		case tc.err != "":
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("expected error containing %q, got: %v", tc.err, err)
+			}
+		case err != nil:
+			t.Errorf("unexpected error: %s", err)
+		case (creds != nil) != tc.tls:
+			t.Errorf("%v: expected tls=%v, got credentials: %v", tc.raw, tc.tls, creds)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/checker"
+	"github.com/google/cel-go/checker/decls"
+	"github.com/graphikDB/trigger"
+	"github.com/pkg/errors"
+	expr "google.golang.org/genproto/googleapis/api/expr/v1alpha1"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// celPosition is the 1-based line & column of a character in a CEL expression
+type celPosition struct {
+	line   int
+	column int
+}
+
+func (p celPosition) String() string {
+	if p.line == 1 {
+		return fmt.Sprintf("column %d", p.column)
+	}
+	return fmt.Sprintf("line %d, column %d", p.line, p.column)
+}
+
+// celIssue matches an error line of the issues reported by cel-go ex: ERROR: <input>:1:6: undeclared reference to 'that'
+var celIssue = regexp.MustCompile(`(?m)^ERROR: <input>:(\d+):(\d+): (.*)$`)
+
+// celMaxArgs is the most arguments a graphik function takes, counting the one it's called on
+const celMaxArgs = 3
+
+// celTypeEnv declares the same variables & functions as graphik's CEL environment: it only infers the type an
+// expression evaluates to once graphik compiled it, so every function takes & returns dyn values
+var celTypeEnv = func() *cel.Env {
+	var names []string
+	for name := range trigger.Functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	declarations := []*expr.Decl{
+		decls.NewVar("this", decls.NewMapType(decls.String, decls.Any)),
+	}
+	for _, name := range names {
+		var overloads []*expr.Decl_FunctionDecl_Overload
+		for arity := 0; arity <= celMaxArgs; arity++ {
+			args := make([]*expr.Type, arity)
+			for i := range args {
+				args[i] = decls.Dyn
+			}
+			overloads = append(overloads, decls.NewOverload(fmt.Sprintf("%s_%d", name, arity), args, decls.Dyn))
+			if arity > 0 {
+				overloads = append(overloads, decls.NewInstanceOverload(fmt.Sprintf("%s_instance_%d", name, arity), args, decls.Dyn))
+			}
+		}
+		declarations = append(declarations, decls.NewFunction(name, overloads...))
+	}
+	env, err := cel.NewEnv(cel.Declarations(declarations...))
+	if err != nil {
+		panic(err)
+	}
+	return env
+}()
+
+// celError formats the issues of an expression graphik failed to compile with the position of each issue
+func celError(err error) error {
+	matches := celIssue.FindAllStringSubmatch(err.Error(), -1)
+	if len(matches) == 0 {
+		return err
+	}
+	var messages []string
+	for _, match := range matches {
+		line, _ := strconv.Atoi(match[1])
+		column, _ := strconv.Atoi(match[2])
+		messages = append(messages, fmt.Sprintf("%s at %s", match[3], celPosition{line: line, column: column}))
+	}
+	return errors.New(strings.Join(messages, "; "))
+}
+
+// compileCEL compiles a CEL expression the way graphik does with compile & returns the type it evaluates to
+func compileCEL(expression string, compile func(expression string) error) (*expr.Type, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, errors.New("expression is empty")
+	}
+	if err := compile(expression); err != nil {
+		return nil, celError(err)
+	}
+	ast, issues := celTypeEnv.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, celError(issues.Err())
+	}
+	return ast.ResultType(), nil
+}
+
+// validateCELType compiles a CEL expression & rejects it unless it evaluates to the expected type (or one only known at runtime)
+func validateCELType(i interface{}, k string, compile func(expression string) error, expected func(t *expr.Type) bool, description string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{errors.Errorf("expected type of %s to be string", k)}
+	}
+	result, err := compileCEL(v, compile)
+	if err != nil {
+		return nil, []error{errors.Wrapf(err, "%s is not a valid CEL expression", k)}
+	}
+	// the values of this & the results of graphik's functions are only typed at runtime
+	runtime := result.GetDyn() != nil || result.GetWellKnown() == expr.Type_ANY
+	if !runtime && !expected(result) {
+		return nil, []error{errors.Errorf("%s must evaluate to %s: got %s", k, description, checker.FormatCheckedType(result))}
+	}
+	return nil, nil
+}
+
+// validateCEL is a ValidateFunc that rejects CEL expressions graphik can't compile as a decision or that don't evaluate to a bool
+func validateCEL(i interface{}, k string) ([]string, []error) {
+	return validateCELType(i, k, func(expression string) error {
+		_, err := trigger.NewDecision(expression)
+		return err
+	}, func(t *expr.Type) bool {
+		return t.GetPrimitive() == expr.Type_BOOL
+	}, "a bool")
+}
+
+// validateCELMap is a ValidateFunc that rejects CEL expressions graphik can't compile as a trigger or that don't evaluate to a map of attributes
+func validateCELMap(i interface{}, k string) ([]string, []error) {
+	return validateCELType(i, k, func(expression string) error {
+		_, err := trigger.NewTrigger(nil, expression)
+		return err
+	}, func(t *expr.Type) bool {
+		return t.GetMapType() != nil
+	}, "a map of attributes")
+}
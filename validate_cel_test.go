@@ -0,0 +1,57 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateCEL(t *testing.T) {
+	for _, tc := range []struct {
+		expression string
+		trigger    bool
+		err        string
+	}{
+		{expression: "this.attributes.priority in ['low', 'medium', 'high']"},
+		{expression: "this.attributes.title.contains(')') && this.gtype != 'task'"},
+		{expression: "this.attributes.count >= 1 // at least one"},
+		{expression: "", err: "expression is empty"},
+		{expression: "this.attributes.priority == 'low", err: "token recognition error at: ''low' at column 29"},
+		{expression: "has(this.attributes.title", err: "missing ')' at '<EOF>' at column 26"},
+		{expression: "has(this.attributes.title]", err: "mismatched input ']' expecting ')' at column 26"},
+		{expression: "this.gtype == 'task')", err: "extraneous input ')' expecting <EOF> at column 21"},
+		{expression: "this.gtype = 'task'", err: "token recognition error at: '= ' at column 12"},
+		{expression: "true &&\n  has(this.attributes.title", err: "missing ')' at '<EOF>' at line 2, column 28"},
+		{expression: "that.gtype == 'task'", err: "undeclared reference to 'that' (in container '') at column 1"},
+		{expression: "this.attributes.title.shout() == 'TASK'", err: "undeclared reference to 'shout' (in container '') at column 28"},
+		{expression: "this.attributes.done"},
+		{expression: "this.attributes.title.upperCase() + '!'", err: "must evaluate to a bool: got string"},
+		{expression: "this.attributes.title.upperCase() == 'TASK'"},
+		{expression: "this.attributes.title.upperCase('TASK')", err: "found no matching overload for 'upperCase' applied to 'dyn.(string)' at column 32"},
+		{expression: "request.method == '/api.DatabaseService/CreateDoc'", err: "undeclared reference to 'request' (in container '') at column 1"},
+		{expression: "this.gtype == 'task' && response.gtype == 'task'", err: "undeclared reference to 'response' (in container '') at column 25"},
+		{expression: "{'method': request.method}", trigger: true, err: "undeclared reference to 'request' (in container '') at column 12"},
+		{expression: "this.attributes.email.sha256() != ''"},
+		{expression: "{'updated_at': now()}", trigger: true},
+		{expression: "this.attributes.done ? {'done_at': now()} : {}", trigger: true},
+		{expression: "this.attributes.payload.jsonDecode()", trigger: true},
+		{expression: "this.attributes", trigger: true},
+		{expression: "true => {'updated_at': now()}", trigger: true, err: "token recognition error at: '=>' at column 6"},
+		{expression: "['updated_at']", trigger: true, err: "must evaluate to a map of attributes: got list(string)"},
+		{expression: "'updated_at'", trigger: true, err: "got string"},
+		{expression: "true", trigger: true, err: "got bool"},
+	} {
+		validate := validateCEL
+		if tc.trigger {
+			validate = validateCELMap
+		}
+		_, errs := validate(tc.expression, "expression")
+		switch {
+		case tc.err == "" && len(errs) > 0:
+			t.Errorf("%q: unexpected error: %s", tc.expression, errs[0])
+		case tc.err != "" && len(errs) == 0:
+			t.Errorf("%q: expected error: %s", tc.expression, tc.err)
+		case tc.err != "" && !strings.Contains(errs[0].Error(), tc.err):
+			t.Errorf("%q: expected error containing %q, got: %s", tc.expression, tc.err, errs[0])
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	apipb "github.com/graphikDB/graphik/gen/grpc/go"
+	"github.com/pkg/errors"
+	"regexp"
+	"sort"
+)
+
+// grpcMethodPattern matches the full name of a gRPC method: /<package>.<service>/<method>
+var grpcMethodPattern = regexp.MustCompile(`^/[A-Za-z_][A-Za-z0-9_.]*\.[A-Za-z_][A-Za-z0-9_]*/[A-Za-z_][A-Za-z0-9_]*$`)
+
+// graphikMethods returns the full name of every gRPC method served by graphik, keyed by the full name.
+// graphik compares an authorizer's method to the invoked method exactly, so there are no wildcards to support.
+func graphikMethods() map[string]struct{} {
+	methods := map[string]struct{}{}
+	services := apipb.File_graphik_proto.Services()
+	for i := 0; i < services.Len(); i++ {
+		service := services.Get(i)
+		for j := 0; j < service.Methods().Len(); j++ {
+			methods[fmt.Sprintf("/%s/%s", service.FullName(), service.Methods().Get(j).Name())] = struct{}{}
+		}
+	}
+	return methods
+}
+
+// validateMethod is a ValidateFunc that rejects malformed gRPC method names & warns about methods graphik doesn't serve
+func validateMethod(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{errors.Errorf("expected type of %s to be string", k)}
+	}
+	if !grpcMethodPattern.MatchString(v) {
+		return nil, []error{errors.Errorf("%s must be the full name of a gRPC method (ex: /api.DatabaseService/CreateDoc), got: %q", k, v)}
+	}
+	if _, ok := graphikMethods()[v]; !ok {
+		return []string{fmt.Sprintf("%s %q is not served by graphik so the authorizer will never be invoked%s", k, v, suggestMethod(v))}, nil
+	}
+	return nil, nil
+}
+
+// suggestMethod returns a hint naming the graphik methods closest to the given method
+func suggestMethod(method string) string {
+	var closest []string
+	best := -1
+	for m := range graphikMethods() {
+		distance := levenshtein(method, m)
+		switch {
+		case best == -1 || distance < best:
+			best = distance
+			closest = []string{m}
+		case distance == best:
+			closest = append(closest, m)
+		}
+	}
+	if best > len(method)/3 {
+		return ""
+	}
+	sort.Strings(closest)
+	return fmt.Sprintf(" (did you mean %q?)", closest[0])
+}
+
+// levenshtein returns the number of single character edits needed to turn a into b
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current := make([]int, len(b)+1)
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous = current
+	}
+	return previous[len(b)]
+}
+
+func minInt(values ...int) int {
+	min := values[0]
+	for _, v := range values[1:] {
+		if v < min {
+			min = v
+		}
+	}
+	return min
+}
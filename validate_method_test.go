@@ -0,0 +1,23 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateMethod(t *testing.T) {
+	for _, method := range []string{"/api.DatabaseService/CreateDoc", "/api.DatabaseService/Stream", "/api.RaftService/Ping"} {
+		if warnings, errs := validateMethod(method, "method"); len(warnings) > 0 || len(errs) > 0 {
+			t.Errorf("%s: unexpected warnings=%v errors=%v", method, warnings, errs)
+		}
+	}
+	for _, method := range []string{"CreateDoc", "api.DatabaseService/CreateDoc", "/api.DatabaseService/*", "/DatabaseService/CreateDoc"} {
+		if _, errs := validateMethod(method, "method"); len(errs) == 0 {
+			t.Errorf("%s: expected error", method)
+		}
+	}
+	warnings, errs := validateMethod("/api.DatabaseService/CreateDocz", "method")
+	if len(errs) > 0 || len(warnings) != 1 || !strings.Contains(warnings[0], `did you mean "/api.DatabaseService/CreateDoc"?`) {
+		t.Fatalf("unexpected warnings=%v errors=%v", warnings, errs)
+	}
+}